@@ -0,0 +1,228 @@
+package main
+
+import (
+	"encoding/csv"
+	"flag"
+	"fmt"
+	"math"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/ahmadnaufalhakim/go-minesweeper/minesweeper"
+)
+
+// benchResult gathers the numbers of one difficulty over the seed range
+type benchResult struct {
+	name       string
+	boards     int
+	wins       int
+	solvable   int
+	guesses    []float64
+	solveTimes []float64
+	// ngAttempts holds the attempt GenerateNGBoard accepts for each
+	// seed, 0 when none within the largest budget
+	ngAttempts []int
+}
+
+func runBench(args []string) int {
+	fs := flag.NewFlagSet("bench", flag.ContinueOnError)
+	difficultyList := fs.String("difficulty", "beginner,intermediate,expert", "comma-separated difficulty presets: "+strings.Join(presetDifficulties, ", "))
+	rows := fs.Int("rows", 0, "rows of a custom board, benched after the -difficulty presets when given")
+	cols := fs.Int("cols", 0, "cols of a custom board")
+	mines := fs.Int("mines", 0, "mines of a custom board")
+	boards := fs.Int("n", 100, "boards per difficulty")
+	seed := fs.Int64("seed", 1, "seed of the first board, the next ones counting up from it")
+	strategyName := fs.String("strategy", minesweeper.StrategyNames[0], "bot strategy: "+strings.Join(minesweeper.StrategyNames, ", "))
+	maxComponentSize := fs.Int("max-component-size", MAX_COMPONENT_SIZE, "largest frontier component the solver enumerates")
+	triesList := fs.String("tries", fmt.Sprintf("10,100,%d", TRIES), "comma-separated NG tries budgets, empty to skip NG generation")
+	repairs := fs.Int("repairs", 0, fmt.Sprintf("bomb moves per NG attempt. The game's %d get almost every board accepted on the first attempt, leaving the tries budgets nothing to tell apart", REPAIRS))
+	ngBand := fs.String("ng-band", "any", "NG difficulty band: "+strings.Join(NGBandNames, ", "))
+	asCSV := fs.Bool("csv", false, "print CSV instead of a table")
+	if code, ok := parseCommand(fs, args, "[flags]"); !ok {
+		return code
+	}
+	if fs.NArg() > 0 || *boards < 1 {
+		fs.Usage()
+		return 2
+	}
+
+	// --- Check the flags before running anything ---
+	var configs []minesweeper.DifficultyConfig
+	custom := isSet(fs, "rows", "cols", "mines")
+	if !custom || isSet(fs, "difficulty") {
+		for _, name := range strings.Split(*difficultyList, ",") {
+			cfg, ok := minesweeper.DifficultyMap[strings.TrimSpace(name)]
+			if !ok {
+				return fail(fmt.Errorf("unknown difficulty %q, expected one of %s", name, strings.Join(presetDifficulties, ", ")))
+			}
+			configs = append(configs, cfg)
+		}
+	}
+	if custom {
+		cfg := minesweeper.DifficultyConfig{Rows: *rows, Cols: *cols, BombCount: *mines}
+		if err := cfg.Validate(); err != nil {
+			return fail(err)
+		}
+		configs = append(configs, cfg)
+	}
+	if _, err := minesweeper.NewStrategy(*strategyName, *maxComponentSize, 0); err != nil {
+		return fail(err)
+	}
+	band, ok := NGBands[*ngBand]
+	if !ok {
+		return fail(fmt.Errorf("unknown NG difficulty %q", *ngBand))
+	}
+	var budgets []int
+	if *triesList != "" {
+		for _, s := range strings.Split(*triesList, ",") {
+			tries, err := strconv.Atoi(strings.TrimSpace(s))
+			if err != nil || tries < 1 {
+				return fail(fmt.Errorf("invalid tries budget %q", s))
+			}
+			budgets = append(budgets, tries)
+		}
+		slices.Sort(budgets)
+		budgets = slices.Compact(budgets)
+	}
+
+	// --- Run ---
+	results := make([]*benchResult, 0, len(configs))
+	for _, cfg := range configs {
+		r := &benchResult{name: StatsCategory(cfg, false), boards: *boards}
+		for s := *seed; s < *seed+int64(*boards); s++ {
+			if err := r.add(cfg, s, *strategyName, *maxComponentSize, budgets, *repairs, band); err != nil {
+				return fail(fmt.Errorf("%s, seed %d: %v", r.name, s, err))
+			}
+		}
+		results = append(results, r)
+	}
+
+	// --- Report ---
+	header := []string{"difficulty", "boards", "win_rate", "guesses_avg", "guesses_p50", "guesses_p90", "guesses_p99", "solvable_rate", "solve_ms_avg", "solve_ms_p50", "solve_ms_p90", "solve_ms_p99"}
+	for _, tries := range budgets {
+		header = append(header, fmt.Sprintf("ng_accept_%d", tries))
+	}
+	if len(budgets) > 0 {
+		header = append(header, "ng_attempts_p50", "ng_attempts_p90")
+	}
+	records := [][]string{header}
+	for _, r := range results {
+		records = append(records, r.record(budgets))
+	}
+
+	if *asCSV {
+		w := csv.NewWriter(os.Stdout)
+		if err := w.WriteAll(records); err != nil {
+			return fail(err)
+		}
+		return 0
+	}
+	fmt.Printf("Strategy: %s, seeds %d to %d, max component size %d\n\n", *strategyName, *seed, *seed+int64(*boards)-1, *maxComponentSize)
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', tabwriter.AlignRight)
+	for _, record := range records {
+		fmt.Fprintln(tw, strings.Join(record, "\t")+"\t")
+	}
+	if err := tw.Flush(); err != nil {
+		return fail(err)
+	}
+	return 0
+}
+
+// add benches the board of one seed: the bot plays it, the solver is
+// timed on it, and NG generation runs from the same seed
+func (r *benchResult) add(cfg minesweeper.DifficultyConfig, seed int64, strategyName string, maxComponentSize int, budgets []int, repairs int, band minesweeper.DifficultyBand) error {
+	m, err := minesweeper.GenerateBoardWithStartCell(cfg, seed)
+	if err != nil {
+		return err
+	}
+	start := time.Now()
+	solvable, _, _ := m.DeterministicSolve(maxComponentSize)
+	r.solveTimes = append(r.solveTimes, float64(time.Since(start).Microseconds())/1000)
+	if solvable {
+		r.solvable++
+	}
+
+	strategy, err := minesweeper.NewStrategy(strategyName, maxComponentSize, seed)
+	if err != nil {
+		return err
+	}
+	bot := minesweeper.NewBot(m, strategy)
+	if bot.Run() {
+		r.wins++
+	}
+	r.guesses = append(r.guesses, float64(bot.Guesses))
+
+	if len(budgets) > 0 {
+		attempt, err := minesweeper.NGAttempts(cfg, seed, budgets[len(budgets)-1], repairs, maxComponentSize, band)
+		if err != nil {
+			return err
+		}
+		r.ngAttempts = append(r.ngAttempts, attempt)
+	}
+	return nil
+}
+
+func (r *benchResult) record(budgets []int) []string {
+	percent := func(count int) string {
+		return fmt.Sprintf("%.1f", float64(count)/float64(r.boards)*100)
+	}
+	number := func(v float64) string {
+		return strconv.FormatFloat(v, 'f', 2, 64)
+	}
+
+	record := []string{
+		r.name, strconv.Itoa(r.boards), percent(r.wins),
+		number(mean(r.guesses)), number(percentile(r.guesses, 50)), number(percentile(r.guesses, 90)), number(percentile(r.guesses, 99)),
+		percent(r.solvable),
+		number(mean(r.solveTimes)), number(percentile(r.solveTimes, 50)), number(percentile(r.solveTimes, 90)), number(percentile(r.solveTimes, 99)),
+	}
+	if len(budgets) == 0 {
+		return record
+	}
+
+	accepted := make([]float64, 0, len(r.ngAttempts))
+	for _, attempt := range r.ngAttempts {
+		if attempt > 0 {
+			accepted = append(accepted, float64(attempt))
+		}
+	}
+	for _, tries := range budgets {
+		count := 0
+		for _, attempt := range accepted {
+			if attempt <= float64(tries) {
+				count++
+			}
+		}
+		record = append(record, percent(count))
+	}
+	// Attempt percentiles are over the accepted boards only
+	if len(accepted) == 0 {
+		return append(record, "-", "-")
+	}
+	return append(record, number(percentile(accepted, 50)), number(percentile(accepted, 90)))
+}
+
+func mean(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sum := 0.0
+	for _, v := range values {
+		sum += v
+	}
+	return sum / float64(len(values))
+}
+
+// percentile is the nearest-rank percentile p of values, 0 for none
+func percentile(values []float64, p float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sorted := slices.Sorted(slices.Values(values))
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	return sorted[max(rank, 1)-1]
+}
@@ -0,0 +1,451 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/ahmadnaufalhakim/go-minesweeper/minesweeper"
+	"github.com/gdamore/tcell/v2"
+)
+
+var intToRune = map[int]rune{
+	minesweeper.CLEAR: ' ',
+	minesweeper.BOMB:  '¤',
+	1:                 '1',
+	2:                 '2',
+	3:                 '3',
+	4:                 '4',
+	5:                 '5',
+	6:                 '6',
+	7:                 '7',
+	8:                 '8',
+	9:                 '9',
+}
+
+var borderSets = map[BorderStyle]map[string]rune{
+	BorderThin: {
+		"topLeft":     '┌',
+		"topRight":    '┐',
+		"bottomLeft":  '└',
+		"bottomRight": '┘',
+		"horizontal":  '─',
+		"vertical":    '│',
+		"tUp":         '┴',
+		"tDown":       '┬',
+		"tRight":      '├',
+		"tLeft":       '┤',
+		"cross":       '┼',
+	},
+	BorderThick: {
+		"topLeft":     '╔',
+		"topRight":    '╗',
+		"bottomLeft":  '╚',
+		"bottomRight": '╝',
+		"horizontal":  '═',
+		"vertical":    '║',
+		"tUp":         '╩',
+		"tDown":       '╦',
+		"tRight":      '╠',
+		"tLeft":       '╣',
+		"cross":       '╬',
+	},
+}
+
+func drawBombs(
+	screen tcell.Screen,
+	m *minesweeper.Minesweeper,
+	showInnerBorders bool,
+	screenX, screenY int,
+) {
+	cellWidth, cellHeight := 1, 1
+	if showInnerBorders {
+		cellWidth, cellHeight = 2, 2
+	}
+
+	for _, pos := range m.BombPositions {
+		var (
+			char  rune
+			style tcell.Style
+		)
+		r, c := pos[0], pos[1]
+		cell := m.Grid[r][c]
+		if cell.Flagged {
+			continue
+		}
+		if m.IsWon {
+			char = '⚑'
+			style = FlagStyle
+		} else {
+			char = intToRune[minesweeper.BOMB]
+			style = ValueToCellStyle[cell.Value]
+		}
+		NewSprite(
+			char,
+			screenX+(cellWidth*c+1),
+			screenY+(cellHeight*r+1),
+		).Draw(screen, style)
+	}
+}
+
+func boardOffsets(screen tcell.Screen, m *minesweeper.Minesweeper, showInnerBorders bool) (int, int) {
+	w, h := screen.Size()
+
+	cellWidth, cellHeight := 1, 1
+	if showInnerBorders {
+		cellWidth, cellHeight = 2, 2
+	}
+
+	boardWidth := m.Cols*cellWidth + 2
+	boardHeight := m.Rows*cellHeight + 2
+
+	offsetX := (w-boardWidth)/2 - boardWidth%2
+	offsetY := (h-boardHeight)/2 - boardHeight%2
+	return offsetX, offsetY
+}
+
+func DrawBoard(
+	screen tcell.Screen,
+	m *minesweeper.Minesweeper,
+	border BorderStyle,
+	showInnerBorders bool,
+) {
+	offsetX, offsetY := boardOffsets(screen, m, showInnerBorders)
+	cellWidth, cellHeight := 1, 1
+	if showInnerBorders {
+		cellWidth, cellHeight = 2, 2
+	}
+
+	runes := borderSets[border]
+
+	NewSprite(runes["topLeft"], offsetX, offsetY).
+		Draw(screen, DefaultBorderStyle)
+	for j := 0; j < m.Cols; j++ {
+		NewSprite(runes["horizontal"], offsetX+(cellWidth*j+1), offsetY).
+			Draw(screen, DefaultBorderStyle)
+
+		if j < m.Cols-1 && showInnerBorders {
+			NewSprite(runes["tDown"], offsetX+(cellWidth*j+2), offsetY).
+				Draw(screen, DefaultBorderStyle)
+		} else if j == m.Cols-1 {
+			NewSprite(runes["topRight"], offsetX+(cellWidth*j+2), offsetY).
+				Draw(screen, DefaultBorderStyle)
+		}
+	}
+
+	for i := 0; i < m.Rows; i++ {
+		NewSprite(runes["vertical"], offsetX, offsetY+(cellHeight*i+1)).
+			Draw(screen, DefaultBorderStyle)
+
+		for j := 0; j < m.Cols; j++ {
+			var (
+				char  rune
+				style tcell.Style
+			)
+			cell := &m.Grid[i][j]
+			if !cell.Revealed {
+				if cell.Flagged {
+					if m.IsGameOver && cell.Value != minesweeper.BOMB {
+						char = '×'
+					} else {
+						char = '⚑'
+					}
+					style = FlagStyle
+				} else {
+					if m.StartCell == cell {
+						char = '✓'
+						style = StartCellStyle
+					} else {
+						char = ' '
+						style = DefaultBorderStyle
+					}
+				}
+			} else {
+				char = intToRune[cell.Value]
+				style = ValueToCellStyle[cell.Value]
+			}
+			NewSprite(char, offsetX+(cellWidth*j+1), offsetY+(cellHeight*i+1)).
+				Draw(screen, style)
+			NewSprite(runes["vertical"], offsetX+(cellWidth*j+2), offsetY+(cellHeight*i+1)).
+				Draw(screen, DefaultBorderStyle)
+		}
+
+		if i < m.Rows-1 && showInnerBorders {
+			NewSprite(runes["tRight"], offsetX, offsetY+(cellHeight*i+2)).
+				Draw(screen, DefaultBorderStyle)
+
+			for j := 0; j < m.Cols; j++ {
+				NewSprite(runes["horizontal"], offsetX+(cellWidth*j+1), offsetY+(cellHeight*i+2)).
+					Draw(screen, DefaultBorderStyle)
+
+				if j < m.Cols-1 {
+					NewSprite(runes["cross"], offsetX+(cellWidth*j+2), offsetY+(cellHeight*i+2)).
+						Draw(screen, DefaultBorderStyle)
+				} else {
+					NewSprite(runes["tLeft"], offsetX+(cellWidth*j+2), offsetY+(cellHeight*i+2)).
+						Draw(screen, DefaultBorderStyle)
+				}
+			}
+		} else if i == m.Rows-1 {
+			NewSprite(runes["bottomLeft"], offsetX, offsetY+(cellHeight*i+2)).
+				Draw(screen, DefaultBorderStyle)
+
+			for j := 0; j < m.Cols; j++ {
+				NewSprite(runes["horizontal"], offsetX+(cellWidth*j+1), offsetY+(cellHeight*i+2)).
+					Draw(screen, DefaultBorderStyle)
+
+				if j < m.Cols-1 {
+					NewSprite(runes["tUp"], offsetX+(cellWidth*j+2), offsetY+(cellHeight*i+2)).
+						Draw(screen, DefaultBorderStyle)
+				} else {
+					NewSprite(runes["bottomRight"], offsetX+(cellWidth*j+2), offsetY+(cellHeight*i+2)).
+						Draw(screen, DefaultBorderStyle)
+				}
+			}
+		}
+	}
+
+	if m.IsGameOver {
+		drawBombs(screen, m, showInnerBorders, offsetX, offsetY)
+	}
+}
+
+func DrawSmiley(
+	screen tcell.Screen,
+	m *minesweeper.Minesweeper,
+	style tcell.Style,
+	showInnerBorders bool,
+	lastMouseButtons tcell.ButtonMask,
+	namePrompt string,
+) {
+	_, offsetY := boardOffsets(screen, m, showInnerBorders)
+
+	if m.IsGameOver {
+		var message string
+		if m.IsWon {
+			message = "You win!"
+			if m.HintsUsed == 1 {
+				message = "You win with 1 hint!"
+			} else if m.HintsUsed > 1 {
+				message = fmt.Sprintf("You win with %d hints!", m.HintsUsed)
+			}
+			if m.Unranked {
+				message += " (unranked)"
+			}
+			DrawCentered(screen, offsetY-3, style, "😎")
+		} else {
+			message = "You lose!"
+			if m.Practice {
+				message += " Press 'u' to undo."
+			}
+			DrawCentered(screen, offsetY-3, style, "😭")
+		}
+		DrawCentered(screen, offsetY-2, style, message)
+		if namePrompt != "" {
+			DrawCentered(screen, offsetY-1, style, namePrompt)
+		} else {
+			DrawCentered(screen, offsetY-1, style, "Press 'r' to create a new board, 'q' to quit to main menu.")
+		}
+	} else if lastMouseButtons == tcell.Button1 {
+		DrawCentered(screen, offsetY-3, style, "😮")
+	} else {
+		DrawCentered(screen, offsetY-3, style, "🙂")
+	}
+}
+
+// DrawHUD draws the remaining mine counter and the game clock on both
+// ends of the smiley row
+func DrawHUD(
+	screen tcell.Screen,
+	m *minesweeper.Minesweeper,
+	showInnerBorders bool,
+) {
+	offsetX, offsetY := boardOffsets(screen, m, showInnerBorders)
+	cellWidth := 1
+	if showInnerBorders {
+		cellWidth = 2
+	}
+	rightX := offsetX + cellWidth*(m.Cols-1) + 2
+
+	mines := fmt.Sprintf("%03d", m.RemainingMines())
+	seconds := min(int(m.ElapsedTime().Seconds()), 999)
+	clock := fmt.Sprintf("%03d", seconds)
+
+	DrawString(screen, offsetX, offsetY-3, HUDStyle, mines)
+	DrawString(screen, rightX-len(clock)+1, offsetY-3, HUDStyle, clock)
+}
+
+func DrawSeed(
+	screen tcell.Screen,
+	m *minesweeper.Minesweeper,
+	style tcell.Style,
+	showInnerBorders bool,
+) {
+	_, offsetY := boardOffsets(screen, m, showInnerBorders)
+	cellHeight := 1
+	if showInnerBorders {
+		cellHeight = 2
+	}
+
+	// Bottom border sits right after the last row of cells
+	bottomY := offsetY + cellHeight*(m.Rows-1) + 2
+	// NG boards are only rebuilt from their seed in NG mode
+	seed := fmt.Sprintf("Seed: %d", m.Seed)
+	if m.NG {
+		seed += " (NG)"
+	}
+	DrawCentered(screen, bottomY+1, style, seed)
+}
+
+// DrawHint highlights the hinted cell and explains it below the seed
+func DrawHint(
+	screen tcell.Screen,
+	m *minesweeper.Minesweeper,
+	hint minesweeper.Hint,
+	style tcell.Style,
+	showInnerBorders bool,
+) {
+	x, y := GridToScreen(screen, m, hint.Position[0], hint.Position[1], showInnerBorders)
+	mainc, combc, _, _ := screen.GetContent(x, y)
+
+	var message string
+	if hint.Safe {
+		screen.SetContent(x, y, mainc, combc, HintSafeStyle)
+		message = "Hint: this cell is safe"
+	} else {
+		screen.SetContent(x, y, mainc, combc, HintRiskStyle)
+		message = fmt.Sprintf("Hint: no safe cell, lowest risk is %.0f%% bomb", hint.Probability*100)
+	}
+
+	_, offsetY := boardOffsets(screen, m, showInnerBorders)
+	cellHeight := 1
+	if showInnerBorders {
+		cellHeight = 2
+	}
+	bottomY := offsetY + cellHeight*(m.Rows-1) + 2
+	DrawCentered(screen, bottomY+2, style, message)
+}
+
+// DrawExplanation highlights the numbers behind a hint and shows the
+// reasoning in a panel beside the board, on the left when the right side
+// is too narrow
+func DrawExplanation(
+	screen tcell.Screen,
+	m *minesweeper.Minesweeper,
+	e HintExplanation,
+	style tcell.Style,
+	showInnerBorders bool,
+) {
+	for _, pos := range e.Supporting {
+		x, y := GridToScreen(screen, m, pos[0], pos[1], showInnerBorders)
+		mainc, combc, _, _ := screen.GetContent(x, y)
+		screen.SetContent(x, y, mainc, combc, HintSupportStyle)
+	}
+
+	w, h := screen.Size()
+	offsetX, offsetY := boardOffsets(screen, m, showInnerBorders)
+	cellWidth := 1
+	if showInnerBorders {
+		cellWidth = 2
+	}
+	boardWidth := m.Cols*cellWidth + 2
+
+	lines := append([]string{e.Title, ""}, e.Lines...)
+	panelWidth := 2*DEFAULT_MARGIN_X + 2
+	for _, line := range lines {
+		panelWidth = max(panelWidth, 2*DEFAULT_MARGIN_X+2+len(line))
+	}
+	panelHeight := 2*DEFAULT_MARGIN_Y + 2 + len(lines)
+
+	x := offsetX + boardWidth + 2
+	if x+panelWidth > w {
+		x = max(offsetX-panelWidth-2, 0)
+	}
+	y := max(min(offsetY, h-panelHeight), 0)
+	DrawFrame(screen, x, y, style, lines, DEFAULT_MARGIN_X, DEFAULT_MARGIN_Y)
+	for i, line := range lines {
+		DrawString(screen, x+1+DEFAULT_MARGIN_X, y+1+DEFAULT_MARGIN_Y+i, style, line)
+	}
+}
+
+// DrawWinStats shows the 3BV of a won board along with the player's
+// 3BV per second and click efficiency, under the seed
+func DrawWinStats(
+	screen tcell.Screen,
+	m *minesweeper.Minesweeper,
+	a minesweeper.Analysis,
+	style tcell.Style,
+	showInnerBorders bool,
+) {
+	_, offsetY := boardOffsets(screen, m, showInnerBorders)
+	cellHeight := 1
+	if showInnerBorders {
+		cellHeight = 2
+	}
+	bottomY := offsetY + cellHeight*(m.Rows-1) + 2
+
+	efficiency := 0
+	if m.Clicks > 0 {
+		efficiency = a.ThreeBV * 100 / m.Clicks
+	}
+
+	DrawCentered(screen, bottomY+2, style, fmt.Sprintf(
+		"3BV: %d | 3BV/s: %.2f | Efficiency: %d%% | Difficulty: %.1f",
+		a.ThreeBV, ThreeBVPerSecond(m, a), efficiency, a.Difficulty,
+	))
+}
+
+func ThreeBVPerSecond(m *minesweeper.Minesweeper, a minesweeper.Analysis) float64 {
+	if seconds := m.ElapsedTime().Seconds(); seconds > 0 {
+		return float64(a.ThreeBV) / seconds
+	}
+	return 0
+}
+
+// GridToScreen returns the screen position of a cell, the inverse of
+// ScreenToGrid
+func GridToScreen(
+	screen tcell.Screen,
+	m *minesweeper.Minesweeper,
+	row, col int,
+	showInnerBorders bool,
+) (screenX, screenY int) {
+	offsetX, offsetY := boardOffsets(screen, m, showInnerBorders)
+	cellWidth, cellHeight := 1, 1
+	if showInnerBorders {
+		cellWidth, cellHeight = 2, 2
+	}
+
+	return offsetX + (cellWidth*col + 1), offsetY + (cellHeight*row + 1)
+}
+
+func ScreenToGrid(
+	screen tcell.Screen,
+	m *minesweeper.Minesweeper,
+	screenX, screenY int,
+	showInnerBorders bool,
+) (row, col int, ok bool) {
+	offsetX, offsetY := boardOffsets(screen, m, showInnerBorders)
+
+	relX := screenX - offsetX
+	relY := screenY - offsetY
+
+	if relX <= 0 || relY <= 0 {
+		return -1, -1, false
+	}
+
+	if showInnerBorders {
+		if relX%2 == 0 || relY%2 == 0 {
+			return -1, -1, false
+		}
+		row = (relY - 1) / 2
+		col = (relX - 1) / 2
+	} else {
+		row = relY - 1
+		col = relX - 1
+	}
+
+	if m.IsOutOfBounds(row, col) {
+		return -1, -1, false
+	}
+
+	return row, col, true
+}
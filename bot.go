@@ -0,0 +1,167 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ahmadnaufalhakim/go-minesweeper/minesweeper"
+	"github.com/gdamore/tcell/v2"
+)
+
+// Delays between two bot moves, from the slowest to the fastest
+var botPaces = []time.Duration{
+	time.Second,
+	500 * time.Millisecond,
+	250 * time.Millisecond,
+	100 * time.Millisecond,
+	50 * time.Millisecond,
+	20 * time.Millisecond,
+}
+
+func drawBotHelpHint(screen tcell.Screen, opts *GameOptions) {
+	DrawHelpHint(screen, opts.Style, "Space = pause, n/Right = step, +/- = pace, r = new board, g = new NG board, q = quit")
+}
+
+// RunBot lets the bot strategy picked in the options play boards of the
+// given difficulty, one move at a time. Its games don't count in the
+// stats, high scores or replays.
+func RunBot(screen tcell.Screen, cfg minesweeper.DifficultyConfig, seed int64, opts *GameOptions) GameState {
+	screen.EnableMouse(tcell.MouseButtonEvents)
+	StopAllSounds()
+
+	// The pace can be changed while watching, it's saved on the way out
+	pace := opts.BotPace
+	defer func() {
+		if opts.BotPace != pace {
+			saveOptions(screen, opts)
+		}
+	}()
+
+	ticker := time.NewTicker(botPaces[len(botPaces)-1])
+	defer ticker.Stop()
+
+	var m *minesweeper.Minesweeper
+	var bot *minesweeper.Bot
+	var cursor *Cursor
+	paused := false
+	// stuck is set when the strategy has no move left on a game not over
+	stuck := false
+	lastMove := time.Now()
+
+	start := func(board *minesweeper.Minesweeper) error {
+		strategy, err := minesweeper.NewStrategy(opts.BotStrategy, MAX_COMPONENT_SIZE, board.Seed)
+		if err != nil {
+			return err
+		}
+		m, bot, cursor = board, minesweeper.NewBot(board, strategy), NewCursor(board)
+		stuck = false
+		lastMove = time.Now()
+		return nil
+	}
+	step := func() {
+		move, ok := bot.Step()
+		lastMove = time.Now()
+		if !ok {
+			stuck = !m.IsGameOver
+			return
+		}
+		if move.Kind != minesweeper.ActionFlag {
+			playRevealSound(m)
+		}
+		cursor.Row, cursor.Col = move.Row, move.Col
+	}
+
+	board, err := minesweeper.GenerateBoardWithStartCell(cfg, seed)
+	if err == nil {
+		err = start(board)
+	}
+	if err != nil {
+		ShowOverlay(
+			screen, FailedOverlayStyle,
+			[]string{
+				"Failed to start the bot!😭",
+				err.Error(),
+			},
+		)
+		return StateMenu
+	}
+
+	for {
+		if !paused && !stuck && !m.IsGameOver && time.Since(lastMove) >= opts.BotPace {
+			step()
+		}
+
+		// --- Draw ---
+		screen.Clear()
+		DrawBackground(screen, opts.Background, m.IsGameOver && !m.IsWon)
+		DrawBoard(screen, m, opts.BorderStyle, opts.ShowInnerBorders)
+		DrawCursor(screen, m, cursor, opts.ShowInnerBorders)
+		DrawSmiley(screen, m, opts.Style, opts.ShowInnerBorders, tcell.ButtonNone, "")
+		DrawHUD(screen, m, opts.ShowInnerBorders)
+		DrawSeed(screen, m, opts.Style, opts.ShowInnerBorders)
+
+		status := fmt.Sprintf("Bot: %s, pace %v, moves %d, guesses %d", opts.BotStrategy, opts.BotPace, bot.Moves, bot.Guesses)
+		if stuck {
+			status += " (stuck)"
+		} else if paused {
+			status += " (paused)"
+		}
+		_, h := screen.Size()
+		DrawCentered(screen, h-2, opts.Style, status)
+		drawBotHelpHint(screen, opts)
+		screen.Show()
+
+		select {
+		case ev := <-screenEventCh:
+			switch ev := ev.(type) {
+			case *tcell.EventResize:
+				screen.Sync()
+			case *tcell.EventKey:
+				switch ev.Key() {
+				case tcell.KeyEsc:
+					return StateMenu
+				case tcell.KeyRight:
+					step()
+					paused = true
+				case tcell.KeyRune:
+					switch ev.Rune() {
+					case ' ':
+						paused = !paused
+					case 'n':
+						step()
+						paused = true
+					case '+', '=':
+						if opts.botPaceIndex < len(botPaces)-1 {
+							opts.NextBotPace(1)
+						}
+					case '-':
+						if opts.botPaceIndex > 0 {
+							opts.NextBotPace(-1)
+						}
+					case 'r':
+						StopAllSounds()
+						board, err := minesweeper.GenerateBoardWithStartCell(cfg, minesweeper.NewSeed())
+						if err == nil {
+							err = start(board)
+						}
+						if err != nil {
+							ShowOverlay(screen, FailedOverlayStyle, []string{"Failed to start the bot!😭", err.Error()})
+						}
+					case 'g':
+						StopAllSounds()
+						// A cancelled generation keeps the current board
+						board := GenerateNGBoardCancellable(screen, cfg, minesweeper.NewSeed(), NGBands[opts.NGBand])
+						if board != nil {
+							if err := start(board); err != nil {
+								ShowOverlay(screen, FailedOverlayStyle, []string{"Failed to start the bot!😭", err.Error()})
+							}
+						}
+					case 'q':
+						return StateMenu
+					}
+				}
+			}
+		case <-ticker.C:
+		}
+	}
+}
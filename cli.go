@@ -0,0 +1,580 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/ahmadnaufalhakim/go-minesweeper/minesweeper"
+)
+
+const PROGRAM_NAME = "go-minesweeper"
+
+// command is a subcommand running without the TUI. run returns the exit
+// code: 0 on success, 1 on failure and 2 on bad usage.
+type command struct {
+	summary string
+	run     func(args []string) int
+}
+
+var commands = map[string]command{
+	"bench":    {"bench a bot strategy, the solver and NG generation over many boards", runBench},
+	"generate": {"print a new board, see minesweeper.Board for the format", runGenerate},
+	"solve":    {"run the solver on a board or on a position read from text", runSolve},
+	"rate":     {"print the 3BV and difficulty of a board", runRate},
+	"replay":   {"play a replay file through and print its outcome", runReplay},
+}
+
+// --- Board flags ---
+
+// boardFlags choose the board to play or to work on
+type boardFlags struct {
+	fs         *flag.FlagSet
+	difficulty string
+	rows       int
+	cols       int
+	mines      int
+	seed       int64
+	ng         bool
+}
+
+func newBoardFlags(fs *flag.FlagSet) *boardFlags {
+	bf := &boardFlags{fs: fs}
+	fs.StringVar(&bf.difficulty, "difficulty", "", "difficulty preset: "+strings.Join(presetDifficulties, ", "))
+	fs.IntVar(&bf.rows, "rows", 0, "rows of a custom board")
+	fs.IntVar(&bf.cols, "cols", 0, "cols of a custom board")
+	fs.IntVar(&bf.mines, "mines", 0, "mines of a custom board")
+	fs.Int64Var(&bf.seed, "seed", -1, "board seed, random when negative. NG boards need -ng as well")
+	fs.BoolVar(&bf.ng, "ng", false, "generate a no-guess board")
+	return bf
+}
+
+// isSet reports whether any of the given flags was on the command line
+func isSet(fs *flag.FlagSet, names ...string) bool {
+	set := false
+	fs.Visit(func(f *flag.Flag) {
+		if slices.Contains(names, f.Name) {
+			set = true
+		}
+	})
+	return set
+}
+
+// requested reports whether a board was asked for at all
+func (bf *boardFlags) requested() bool {
+	return isSet(bf.fs, "difficulty", "rows", "cols", "mines", "seed", "ng")
+}
+
+// config is the preset given by --difficulty, or fallback with its size
+// changed by --rows, --cols and --mines
+func (bf *boardFlags) config(fallback minesweeper.DifficultyConfig) (minesweeper.DifficultyConfig, error) {
+	custom := isSet(bf.fs, "rows", "cols", "mines")
+	if bf.difficulty != "" {
+		if custom {
+			return minesweeper.DifficultyConfig{}, errors.New("-difficulty can't be combined with -rows, -cols and -mines")
+		}
+		cfg, ok := minesweeper.DifficultyMap[bf.difficulty]
+		if !ok {
+			return minesweeper.DifficultyConfig{}, fmt.Errorf("unknown difficulty %q, expected one of %s", bf.difficulty, strings.Join(presetDifficulties, ", "))
+		}
+		return cfg, nil
+	}
+
+	cfg := fallback
+	if isSet(bf.fs, "rows") {
+		cfg.Rows = bf.rows
+	}
+	if isSet(bf.fs, "cols") {
+		cfg.Cols = bf.cols
+	}
+	if isSet(bf.fs, "mines") {
+		cfg.BombCount = bf.mines
+	}
+	if err := cfg.Validate(); err != nil {
+		return minesweeper.DifficultyConfig{}, err
+	}
+	return cfg, nil
+}
+
+func (bf *boardFlags) boardSeed() int64 {
+	if bf.seed < 0 {
+		return minesweeper.NewSeed()
+	}
+	return bf.seed
+}
+
+// generate builds the board the flags ask for, a beginner one by default.
+// NG boards use the same budget as the game, with any difficulty.
+func (bf *boardFlags) generate() (*minesweeper.Minesweeper, error) {
+	cfg, err := bf.config(minesweeper.DifficultyMap["beginner"])
+	if err != nil {
+		return nil, err
+	}
+	seed := bf.boardSeed()
+	if !bf.ng {
+		return minesweeper.GenerateBoardWithStartCell(cfg, seed)
+	}
+
+	minesweeperCh, _ := minesweeper.GenerateNGBoard(context.Background(), cfg, seed, TRIES, REPAIRS, MAX_COMPONENT_SIZE, minesweeper.AnyDifficulty)
+	m := <-minesweeperCh
+	if m == nil {
+		return nil, fmt.Errorf("no NG board found for seed %d within %d tries", seed, TRIES)
+	}
+	m.NG = true
+	return m, nil
+}
+
+// --- Game launch flags ---
+
+// launchFlags are the flags of the game itself. Option flags change the
+// options like the options menu does, board flags skip the menu and start
+// a game right away, on a new board or on one read from a file.
+type launchFlags struct {
+	options bool
+	board   bool
+	cfg     minesweeper.DifficultyConfig
+	seed    int64
+	ng      bool
+	// imported is the board read from the -board file
+	imported *minesweeper.Minesweeper
+}
+
+func parseLaunchFlags(args []string, opts *GameOptions) (*launchFlags, error) {
+	fs := flag.NewFlagSet(PROGRAM_NAME, flag.ContinueOnError)
+	fs.Usage = func() {
+		out := fs.Output()
+		fmt.Fprintf(out, "Usage: %s [flags]\n       %s <command> [flags] [args]\n\nCommands:\n", PROGRAM_NAME, PROGRAM_NAME)
+		for _, name := range slices.Sorted(maps.Keys(commands)) {
+			fmt.Fprintf(out, "  %-10s %s\n", name, commands[name].summary)
+		}
+		fmt.Fprintf(out, "\nRun '%s <command> -h' for the flags of a command.\n\nFlags:\n", PROGRAM_NAME)
+		fs.PrintDefaults()
+	}
+
+	bf := newBoardFlags(fs)
+	boardFile := fs.String("board", "", "play the board of a JSON or text file, see minesweeper.Board")
+	fs.Func("volume", fmt.Sprintf("sound volume, one of %v", volPercentages), func(s string) error {
+		var volume int
+		if _, err := fmt.Sscan(s, &volume); err != nil {
+			return err
+		}
+		return opts.selectVolume(volume)
+	})
+	fs.Func("background", "background: "+strings.Join(backgroundNames(), ", "), opts.selectBackground)
+	fs.Func("border", "border style: "+strings.Join(borderStyleNames[:], ", "), func(s string) error {
+		b, err := ParseBorderStyle(s)
+		if err != nil {
+			return err
+		}
+		opts.BorderStyle = b
+		return nil
+	})
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+	if fs.NArg() > 0 {
+		err := fmt.Errorf("unknown command %q", fs.Arg(0))
+		fmt.Fprintln(fs.Output(), err)
+		fs.Usage()
+		return nil, err
+	}
+
+	launch := &launchFlags{
+		options: isSet(fs, "volume", "background", "border"),
+		board:   bf.requested(),
+		ng:      bf.ng,
+	}
+	if *boardFile != "" {
+		if launch.board {
+			err := errors.New("-board can't be combined with the other board flags")
+			fmt.Fprintln(fs.Output(), err)
+			return nil, err
+		}
+		m, err := LoadBoardFile(*boardFile)
+		if err != nil {
+			fmt.Fprintln(fs.Output(), err)
+			return nil, err
+		}
+		launch.imported = m
+	} else if launch.board {
+		cfg, err := bf.config(opts.Difficulty)
+		if err != nil {
+			fmt.Fprintln(fs.Output(), err)
+			return nil, err
+		}
+		launch.cfg = cfg
+		launch.seed = bf.boardSeed()
+	}
+	return launch, nil
+}
+
+// --- Subcommands ---
+
+// parseCommand parses the flags of a subcommand, returning the exit code
+// to stop with when they're wrong or help was asked for
+func parseCommand(fs *flag.FlagSet, args []string, usage string) (int, bool) {
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s %s %s\n\nFlags:\n", PROGRAM_NAME, fs.Name(), usage)
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0, false
+		}
+		return 2, false
+	}
+	return 0, true
+}
+
+func fail(err error) int {
+	fmt.Fprintf(os.Stderr, "%s: %v\n", PROGRAM_NAME, err)
+	return 1
+}
+
+func writeJSON(w io.Writer, v any) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+// describeBoard names the board the way the stats do, with its seed
+func describeBoard(m *minesweeper.Minesweeper) string {
+	category := StatsCategory(minesweeper.DifficultyConfig{Rows: m.Rows, Cols: m.Cols, BombCount: m.BombCount}, m.NG)
+	return fmt.Sprintf("%s (%dx%d, %d mines), seed %d", category, m.Rows, m.Cols, m.BombCount, m.Seed)
+}
+
+func runGenerate(args []string) int {
+	fs := flag.NewFlagSet("generate", flag.ContinueOnError)
+	bf := newBoardFlags(fs)
+	asJSON := fs.Bool("json", false, "print JSON instead of text")
+	if code, ok := parseCommand(fs, args, "[flags]"); !ok {
+		return code
+	}
+
+	m, err := bf.generate()
+	if err != nil {
+		return fail(err)
+	}
+
+	board := m.Board()
+	if *asJSON {
+		err = board.Save(os.Stdout)
+	} else {
+		fmt.Printf("# %s\n", describeBoard(m))
+		err = board.WriteText(os.Stdout)
+	}
+	if err != nil {
+		return fail(err)
+	}
+	return 0
+}
+
+type solveOutput struct {
+	Board       string  `json:"board"`
+	Solvable    bool    `json:"solvable"`
+	Revealed    int     `json:"revealed"`
+	SafeCells   int     `json:"safeCells"`
+	Flagged     int     `json:"flagged"`
+	Mines       int     `json:"mines"`
+	SolveTimeMs float64 `json:"solveTimeMs"`
+}
+
+func runSolve(args []string) int {
+	fs := flag.NewFlagSet("solve", flag.ContinueOnError)
+	bf := newBoardFlags(fs)
+	asJSON := fs.Bool("json", false, "print JSON instead of text")
+	trace := fs.Bool("trace", false, "print the deduction steps of a position read from text")
+	if code, ok := parseCommand(fs, args, "[flags] [FILE | -]"); !ok {
+		return code
+	}
+	if fs.NArg() > 1 {
+		fs.Usage()
+		return 2
+	}
+	// A position read from text, from stdin unless a board is asked for.
+	// -mines alone is the mine count of the position.
+	generated := isSet(fs, "difficulty", "rows", "cols", "seed", "ng")
+	if fs.NArg() == 1 && generated {
+		return fail(errors.New("only -mines applies to a position read from text"))
+	}
+	if !generated {
+		return solveText(fs.Arg(0), bf.mines, *trace, *asJSON)
+	}
+
+	m, err := bf.generate()
+	if err != nil {
+		return fail(err)
+	}
+
+	start := time.Now()
+	solvable, revealed, flagged := m.DeterministicSolve(MAX_COMPONENT_SIZE)
+	out := solveOutput{
+		Board:       describeBoard(m),
+		Solvable:    solvable,
+		Revealed:    len(revealed),
+		SafeCells:   m.Rows*m.Cols - m.BombCount,
+		Flagged:     len(flagged),
+		Mines:       m.BombCount,
+		SolveTimeMs: float64(time.Since(start).Microseconds()) / 1000,
+	}
+
+	if *asJSON {
+		if err := writeJSON(os.Stdout, out); err != nil {
+			return fail(err)
+		}
+		return 0
+	}
+	fmt.Printf("Board:      %s\n", out.Board)
+	fmt.Printf("Solvable:   %v\n", out.Solvable)
+	fmt.Printf("Revealed:   %d/%d safe cells\n", out.Revealed, out.SafeCells)
+	fmt.Printf("Flagged:    %d/%d mines\n", out.Flagged, out.Mines)
+	fmt.Printf("Solve time: %.3f ms\n", out.SolveTimeMs)
+	return 0
+}
+
+type cellProbability struct {
+	Row         int     `json:"row"`
+	Col         int     `json:"col"`
+	Probability float64 `json:"probability"`
+}
+
+type solveTextOutput struct {
+	Rows          int                `json:"rows"`
+	Cols          int                `json:"cols"`
+	BombCount     int                `json:"bombCount,omitempty"`
+	Consistent    bool               `json:"consistent"`
+	Solvable      bool               `json:"solvable"`
+	Safe          [][2]int           `json:"safe"`
+	Mines         [][2]int           `json:"mines"`
+	Undecided     [][2]int           `json:"undecided"`
+	Probabilities []cellProbability  `json:"probabilities"`
+	Trace         []minesweeper.Step `json:"trace,omitempty"`
+}
+
+// solveText solves a position written in the minesweeper.ParsePosition
+// notation, read from a file or from stdin for "" and "-". The position
+// is solvable when every unknown cell is forced one way or the other.
+// With trace, the steps of the solver are given too.
+func solveText(path string, bombCount int, trace, asJSON bool) int {
+	in := os.Stdin
+	if path != "" && path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return fail(err)
+		}
+		defer f.Close()
+		in = f
+	}
+	p, err := minesweeper.ParsePosition(in)
+	if err != nil {
+		return fail(err)
+	}
+	p.BombCount = bombCount
+
+	result := minesweeper.SolvePosition(p, MAX_COMPONENT_SIZE)
+	out := solveTextOutput{
+		Rows:          p.Rows,
+		Cols:          p.Cols,
+		BombCount:     p.BombCount,
+		Consistent:    result.Consistent,
+		Solvable:      result.Consistent && len(result.Undecided) == 0,
+		Safe:          result.Safe,
+		Mines:         result.Mines,
+		Undecided:     result.Undecided,
+		Probabilities: make([]cellProbability, 0, len(result.Probabilities)),
+	}
+	for _, pos := range result.Undecided {
+		if probability, ok := result.Probabilities[pos]; ok {
+			out.Probabilities = append(out.Probabilities, cellProbability{pos[0], pos[1], probability})
+		}
+	}
+	if trace {
+		out.Trace = result.Trace
+	}
+
+	if asJSON {
+		if err := writeJSON(os.Stdout, out); err != nil {
+			return fail(err)
+		}
+		return 0
+	}
+
+	cells := func(positions [][2]int) string {
+		strs := make([]string, len(positions))
+		for i, pos := range positions {
+			strs[i] = fmt.Sprintf("%d,%d", pos[0], pos[1])
+		}
+		return strings.Join(strs, " ")
+	}
+	mines := "unknown mine count"
+	if p.BombCount > 0 {
+		mines = fmt.Sprintf("%d mines", p.BombCount)
+	}
+	fmt.Printf("Position:   %dx%d, %s\n", p.Rows, p.Cols, mines)
+	if !out.Consistent {
+		fmt.Println("Consistent: false, no mine layout matches the position")
+		return 0
+	}
+	fmt.Println("Consistent: true")
+	if out.Solvable {
+		fmt.Println("Solvable:   true")
+	} else {
+		fmt.Printf("Solvable:   false, %d cells undecided\n", len(out.Undecided))
+	}
+	fmt.Printf("Safe (%d):  %s\n", len(out.Safe), cells(out.Safe))
+	fmt.Printf("Mines (%d): %s\n", len(out.Mines), cells(out.Mines))
+	if len(out.Trace) > 0 {
+		fmt.Println("Trace:")
+		for i, step := range out.Trace {
+			fmt.Printf("%d. %s\n", i+1, step.Kind)
+			for _, line := range step.Lines() {
+				fmt.Println(strings.TrimRight("   "+line, " "))
+			}
+		}
+	}
+	if len(out.Probabilities) > 0 {
+		fmt.Println("Probabilities:")
+		tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', tabwriter.AlignRight)
+		fmt.Fprintln(tw, "Row\tCol\tMine\t")
+		for _, cp := range out.Probabilities {
+			fmt.Fprintf(tw, "%d\t%d\t%.1f%%\t\n", cp.Row, cp.Col, cp.Probability*100)
+		}
+		tw.Flush()
+	}
+	return 0
+}
+
+type rateOutput struct {
+	Board             string  `json:"board"`
+	ThreeBV           int     `json:"threeBV"`
+	Openings          int     `json:"openings"`
+	Islands           int     `json:"islands"`
+	Solvable          bool    `json:"solvable"`
+	TrivialRounds     int     `json:"trivialRounds"`
+	SubsetRounds      int     `json:"subsetRounds"`
+	EliminationRounds int     `json:"eliminationRounds"`
+	SearchRounds      int     `json:"searchRounds"`
+	GlobalRounds      int     `json:"globalRounds"`
+	Difficulty        float64 `json:"difficulty"`
+}
+
+func runRate(args []string) int {
+	fs := flag.NewFlagSet("rate", flag.ContinueOnError)
+	bf := newBoardFlags(fs)
+	asJSON := fs.Bool("json", false, "print JSON instead of text")
+	if code, ok := parseCommand(fs, args, "[flags]"); !ok {
+		return code
+	}
+
+	m, err := bf.generate()
+	if err != nil {
+		return fail(err)
+	}
+
+	a := m.Analyze(MAX_COMPONENT_SIZE)
+	out := rateOutput{
+		Board:             describeBoard(m),
+		ThreeBV:           a.ThreeBV,
+		Openings:          a.Openings,
+		Islands:           a.Islands,
+		Solvable:          a.Solvable,
+		TrivialRounds:     a.Stats.TrivialRounds,
+		SubsetRounds:      a.Stats.SubsetRounds,
+		EliminationRounds: a.Stats.EliminationRounds,
+		SearchRounds:      a.Stats.SearchRounds,
+		GlobalRounds:      a.Stats.GlobalRounds,
+		Difficulty:        a.Difficulty,
+	}
+
+	if *asJSON {
+		if err := writeJSON(os.Stdout, out); err != nil {
+			return fail(err)
+		}
+		return 0
+	}
+	fmt.Printf("Board:      %s\n", out.Board)
+	fmt.Printf("3BV:        %d (%d openings, %d islands)\n", out.ThreeBV, out.Openings, out.Islands)
+	fmt.Printf("Solvable:   %v\n", out.Solvable)
+	fmt.Printf("Rounds:     %d trivial, %d subset, %d elimination, %d search, %d global\n", out.TrivialRounds, out.SubsetRounds, out.EliminationRounds, out.SearchRounds, out.GlobalRounds)
+	fmt.Printf("Difficulty: %.1f\n", out.Difficulty)
+	return 0
+}
+
+type replayOutput struct {
+	Board            string  `json:"board"`
+	Moves            int     `json:"moves"`
+	Clicks           int     `json:"clicks"`
+	DurationMs       int64   `json:"durationMs"`
+	Result           string  `json:"result"`
+	ThreeBVPerSecond float64 `json:"threeBVPerSecond,omitempty"`
+}
+
+func runReplay(args []string) int {
+	fs := flag.NewFlagSet("replay", flag.ContinueOnError)
+	asJSON := fs.Bool("json", false, "print JSON instead of text")
+	if code, ok := parseCommand(fs, args, "[flags] FILE"); !ok {
+		return code
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return 2
+	}
+
+	f, err := os.Open(fs.Arg(0))
+	if err != nil {
+		return fail(err)
+	}
+	defer f.Close()
+	r, err := minesweeper.LoadReplay(f)
+	if err != nil {
+		return fail(err)
+	}
+	m, err := r.NewBoard()
+	if err != nil {
+		return fail(err)
+	}
+	for _, a := range r.Actions {
+		m.Play(a.Kind, a.Row, a.Col)
+	}
+	// The clock is the recorded one, not the time playing it back took
+	m.StopClock()
+	m.Elapsed = r.Duration()
+
+	out := replayOutput{
+		Board:      describeBoard(m),
+		Moves:      len(r.Actions),
+		Clicks:     m.Clicks,
+		DurationMs: r.Duration().Milliseconds(),
+		Result:     "unfinished",
+	}
+	if m.IsWon {
+		out.Result = "won"
+		out.ThreeBVPerSecond = ThreeBVPerSecond(m, m.Analyze(MAX_COMPONENT_SIZE))
+	} else if m.IsGameOver {
+		out.Result = "lost"
+	}
+
+	if *asJSON {
+		if err := writeJSON(os.Stdout, out); err != nil {
+			return fail(err)
+		}
+		return 0
+	}
+	fmt.Printf("Board:    %s\n", out.Board)
+	fmt.Printf("Moves:    %d (%d clicks)\n", out.Moves, out.Clicks)
+	fmt.Printf("Duration: %s\n", r.Duration().Round(time.Millisecond))
+	fmt.Printf("Result:   %s\n", out.Result)
+	if m.IsWon {
+		fmt.Printf("3BV/s:    %.2f\n", out.ThreeBVPerSecond)
+	}
+	return 0
+}
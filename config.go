@@ -0,0 +1,174 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/ahmadnaufalhakim/go-minesweeper/minesweeper"
+	"github.com/gdamore/tcell/v2"
+)
+
+// CONFIG_VERSION is bumped whenever the config file layout changes
+const CONFIG_VERSION = 1
+
+var ErrInvalidConfig = errors.New("config file is invalid")
+
+type savedDifficulty struct {
+	Rows      int `json:"rows"`
+	Cols      int `json:"cols"`
+	BombCount int `json:"bombCount"`
+}
+
+type savedConfig struct {
+	Version           int             `json:"version"`
+	BorderStyle       int             `json:"borderStyle"`
+	ShowInnerBorders  bool            `json:"showInnerBorders"`
+	Background        string          `json:"background"`
+	Volume            int             `json:"volume"`
+	Difficulty        savedDifficulty `json:"difficulty"`
+	NGBand            string          `json:"ngBand"`
+	Practice          bool            `json:"practice"`
+	DifficultyIndex   int             `json:"difficultyIndex"`
+	DifficultyNGIndex int             `json:"difficultyNGIndex"`
+	CustomDifficulty  savedDifficulty `json:"customDifficulty"`
+	PlayerName        string          `json:"playerName"`
+	BotStrategy       string          `json:"botStrategy"`
+	BotPaceMs         int64           `json:"botPaceMs"`
+}
+
+func (d savedDifficulty) config() minesweeper.DifficultyConfig {
+	return minesweeper.DifficultyConfig{Rows: d.Rows, Cols: d.Cols, BombCount: d.BombCount}
+}
+
+func newSavedDifficulty(cfg minesweeper.DifficultyConfig) savedDifficulty {
+	return savedDifficulty{Rows: cfg.Rows, Cols: cfg.Cols, BombCount: cfg.BombCount}
+}
+
+// LoadOptions reads the options from the config file, an absent file
+// meaning the defaults. Every value is checked against what the menus
+// offer, so an invalid file is reported rather than half applied.
+func LoadOptions() (*GameOptions, error) {
+	path, err := dataPath(CONFIG_FILE_NAME)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return NewGameOptions(), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Values missing from the file keep their defaults, so files written
+	// before an option was added stay valid
+	c := newSavedConfig(NewGameOptions())
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidConfig, err)
+	}
+	if c.Version != CONFIG_VERSION {
+		return nil, fmt.Errorf("%w: got version %d, expected %d", ErrInvalidConfig, c.Version, CONFIG_VERSION)
+	}
+
+	opts := NewGameOptions()
+	if c.BorderStyle < 0 || c.BorderStyle >= int(borderStyleCount) {
+		return nil, fmt.Errorf("%w: unknown border style %d", ErrInvalidConfig, c.BorderStyle)
+	}
+	opts.BorderStyle = BorderStyle(c.BorderStyle)
+	opts.ShowInnerBorders = c.ShowInnerBorders
+
+	if err := opts.selectBackground(c.Background); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidConfig, err)
+	}
+	if err := opts.selectVolume(c.Volume); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidConfig, err)
+	}
+	if err := opts.selectNGBand(c.NGBand); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidConfig, err)
+	}
+	opts.Practice = c.Practice
+
+	if err := c.Difficulty.config().Validate(); err != nil {
+		return nil, fmt.Errorf("%w: difficulty: %v", ErrInvalidConfig, err)
+	}
+	opts.Difficulty = c.Difficulty.config()
+	if c.DifficultyIndex < 0 || c.DifficultyIndex >= len(difficulties) {
+		return nil, fmt.Errorf("%w: difficulty index %d is out of range", ErrInvalidConfig, c.DifficultyIndex)
+	}
+	opts.DifficultyIndex = c.DifficultyIndex
+	if c.DifficultyNGIndex < 0 || c.DifficultyNGIndex >= len(difficultiesNG) {
+		return nil, fmt.Errorf("%w: NG difficulty index %d is out of range", ErrInvalidConfig, c.DifficultyNGIndex)
+	}
+	opts.DifficultyNGIndex = c.DifficultyNGIndex
+	// The bomb count of the custom difficulty is only checked when a game
+	// starts, like on the custom difficulty page
+	custom := c.CustomDifficulty
+	if custom.Rows < 1 || custom.Rows > minesweeper.MAX_ROWS || custom.Cols < 1 || custom.Cols > minesweeper.MAX_COLS || custom.BombCount < 0 {
+		return nil, fmt.Errorf("%w: invalid custom difficulty %dx%d/%d", ErrInvalidConfig, custom.Rows, custom.Cols, custom.BombCount)
+	}
+	opts.CustomDifficulty = custom.config()
+
+	if len([]rune(c.PlayerName)) > MAX_NAME_LENGTH {
+		return nil, fmt.Errorf("%w: player name is longer than %d characters", ErrInvalidConfig, MAX_NAME_LENGTH)
+	}
+	opts.PlayerName = c.PlayerName
+
+	if err := opts.selectBotStrategy(c.BotStrategy); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidConfig, err)
+	}
+	if err := opts.selectBotPace(time.Duration(c.BotPaceMs) * time.Millisecond); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidConfig, err)
+	}
+
+	return opts, nil
+}
+
+func newSavedConfig(opts *GameOptions) savedConfig {
+	return savedConfig{
+		Version:           CONFIG_VERSION,
+		BorderStyle:       int(opts.BorderStyle),
+		ShowInnerBorders:  opts.ShowInnerBorders,
+		Background:        opts.Background,
+		Volume:            opts.Volume,
+		Difficulty:        newSavedDifficulty(opts.Difficulty),
+		NGBand:            opts.NGBand,
+		Practice:          opts.Practice,
+		DifficultyIndex:   opts.DifficultyIndex,
+		DifficultyNGIndex: opts.DifficultyNGIndex,
+		CustomDifficulty:  newSavedDifficulty(opts.CustomDifficulty),
+		PlayerName:        opts.PlayerName,
+		BotStrategy:       opts.BotStrategy,
+		BotPaceMs:         opts.BotPace.Milliseconds(),
+	}
+}
+
+func SaveOptions(opts *GameOptions) error {
+	path, err := dataPath(CONFIG_FILE_NAME)
+	if err != nil {
+		return err
+	}
+
+	c := newSavedConfig(opts)
+	return writeFileAtomic(path, func(f *os.File) error {
+		enc := json.NewEncoder(f)
+		enc.SetIndent("", "  ")
+		return enc.Encode(c)
+	})
+}
+
+// saveOptions saves the options, telling the player when it failed
+func saveOptions(screen tcell.Screen, opts *GameOptions) {
+	if err := SaveOptions(opts); err != nil {
+		ShowOverlay(
+			screen, FailedOverlayStyle,
+			[]string{
+				"Failed to save the options!😭",
+				err.Error(),
+			},
+		)
+	}
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"github.com/ahmadnaufalhakim/go-minesweeper/minesweeper"
+	"github.com/gdamore/tcell/v2"
+)
+
+type Cursor struct {
+	Row int
+	Col int
+}
+
+// NewCursor places the cursor on the start cell, or in the middle of
+// the board when there's no start cell
+func NewCursor(m *minesweeper.Minesweeper) *Cursor {
+	if m.StartCell != nil {
+		return &Cursor{Row: m.StartCellPosition[0], Col: m.StartCellPosition[1]}
+	}
+	return &Cursor{Row: m.Rows / 2, Col: m.Cols / 2}
+}
+
+func (c *Cursor) Move(m *minesweeper.Minesweeper, dRow, dCol int) {
+	c.Row = max(0, min(m.Rows-1, c.Row+dRow))
+	c.Col = max(0, min(m.Cols-1, c.Col+dCol))
+}
+
+// JumpToEdge moves the cursor as far as possible towards a direction
+func (c *Cursor) JumpToEdge(m *minesweeper.Minesweeper, dRow, dCol int) {
+	c.Move(m, dRow*m.Rows, dCol*m.Cols)
+}
+
+// NextUnrevealed moves the cursor to the next (delta = 1) or previous
+// (delta = -1) cell in reading order that is neither revealed nor flagged
+func (c *Cursor) NextUnrevealed(m *minesweeper.Minesweeper, delta int) {
+	total := m.Rows * m.Cols
+	pos := c.Row*m.Cols + c.Col
+	for range total {
+		pos = (pos + delta + total) % total
+		cell := m.Grid[pos/m.Cols][pos%m.Cols]
+		if !cell.Revealed && !cell.Flagged {
+			c.Row, c.Col = pos/m.Cols, pos%m.Cols
+			return
+		}
+	}
+}
+
+// HandleKey moves the cursor for arrows/WASD/hjkl (shifted or uppercase
+// jumps to the board edge), Home/End, PgUp/PgDn and Tab/Shift+Tab. It
+// reports whether the key was a cursor key.
+func (c *Cursor) HandleKey(ev *tcell.EventKey, m *minesweeper.Minesweeper) bool {
+	move := func(dRow, dCol int, jump bool) {
+		if jump {
+			c.JumpToEdge(m, dRow, dCol)
+		} else {
+			c.Move(m, dRow, dCol)
+		}
+	}
+	shift := ev.Modifiers()&tcell.ModShift != 0
+
+	switch ev.Key() {
+	case tcell.KeyUp:
+		move(-1, 0, shift)
+	case tcell.KeyDown:
+		move(1, 0, shift)
+	case tcell.KeyLeft:
+		move(0, -1, shift)
+	case tcell.KeyRight:
+		move(0, 1, shift)
+	case tcell.KeyHome:
+		c.JumpToEdge(m, 0, -1)
+	case tcell.KeyEnd:
+		c.JumpToEdge(m, 0, 1)
+	case tcell.KeyPgUp:
+		c.JumpToEdge(m, -1, 0)
+	case tcell.KeyPgDn:
+		c.JumpToEdge(m, 1, 0)
+	case tcell.KeyTab:
+		c.NextUnrevealed(m, 1)
+	case tcell.KeyBacktab:
+		c.NextUnrevealed(m, -1)
+	case tcell.KeyRune:
+		switch ev.Rune() {
+		case 'w', 'k':
+			move(-1, 0, false)
+		case 's', 'j':
+			move(1, 0, false)
+		case 'a', 'h':
+			move(0, -1, false)
+		case 'd', 'l':
+			move(0, 1, false)
+		case 'W', 'K':
+			move(-1, 0, true)
+		case 'S', 'J':
+			move(1, 0, true)
+		case 'A', 'H':
+			move(0, -1, true)
+		case 'D', 'L':
+			move(0, 1, true)
+		default:
+			return false
+		}
+	default:
+		return false
+	}
+
+	return true
+}
+
+// DrawCursor highlights the cell under the cursor, keeping whatever
+// character was drawn there
+func DrawCursor(
+	screen tcell.Screen,
+	m *minesweeper.Minesweeper,
+	c *Cursor,
+	showInnerBorders bool,
+) {
+	x, y := GridToScreen(screen, m, c.Row, c.Col, showInnerBorders)
+	mainc, combc, _, _ := screen.GetContent(x, y)
+	screen.SetContent(x, y, mainc, combc, CursorStyle)
+}
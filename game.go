@@ -4,9 +4,13 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"runtime"
+	"slices"
 	"strings"
 	"time"
+	"unicode"
 
+	"github.com/ahmadnaufalhakim/go-minesweeper/minesweeper"
 	"github.com/gdamore/tcell/v2"
 )
 
@@ -16,10 +20,34 @@ type GameOptions struct {
 	ShowInnerBorders bool
 	Background       string
 	Volume           int
-	Difficulty       DifficultyConfig
+	Difficulty       minesweeper.DifficultyConfig
+	NGBand           string
+	Practice         bool
+	// Menu selections, kept across runs
+	DifficultyIndex   int
+	DifficultyNGIndex int
+	CustomDifficulty  minesweeper.DifficultyConfig
+	// PlayerName is offered again on the next high score
+	PlayerName string
+	// Watch bot mode, BotPace being the delay between two moves
+	BotStrategy string
+	BotPace     time.Duration
 
-	bgIndex  int
-	volIndex int
+	bgIndex          int
+	volIndex         int
+	ngBandIndex      int
+	botStrategyIndex int
+	botPaceIndex     int
+}
+
+// NG mode difficulty bands, scored like minesweeper.Analysis.Difficulty
+var NGBandNames = []string{"any", "easy", "medium", "hard"}
+
+var NGBands = map[string]minesweeper.DifficultyBand{
+	"any":    minesweeper.AnyDifficulty,
+	"easy":   {Min: 0, Max: 20},
+	"medium": {Min: 20, Max: 45},
+	"hard":   {Min: 45, Max: 100},
 }
 
 func NewGameOptions() *GameOptions {
@@ -29,11 +57,18 @@ func NewGameOptions() *GameOptions {
 		ShowInnerBorders: false,
 		Background:       "none",
 		Volume:           30,
-		Difficulty:       DifficultyMap["beginner"],
+		Difficulty:       minesweeper.DifficultyMap["beginner"],
+		NGBand:           "any",
+		CustomDifficulty: minesweeper.DifficultyConfig{Rows: 9, Cols: 9, BombCount: 10},
+		BotStrategy:      minesweeper.StrategyNames[0],
+		BotPace:          botPaces[2],
 		//TODO: debug for `ShowInnerBorders = true`
 
-		bgIndex:  0,
-		volIndex: 3,
+		bgIndex:          0,
+		volIndex:         3,
+		ngBandIndex:      0,
+		botStrategyIndex: 0,
+		botPaceIndex:     2,
 	}
 }
 
@@ -41,6 +76,10 @@ func (opts *GameOptions) ToggleInnerBorders() {
 	opts.ShowInnerBorders = !opts.ShowInnerBorders
 }
 
+func (opts *GameOptions) TogglePractice() {
+	opts.Practice = !opts.Practice
+}
+
 func (opts *GameOptions) NextBorderStyle(delta int) {
 	opts.BorderStyle = BorderStyle((int(opts.BorderStyle) + delta + int(borderStyleCount)) % int(borderStyleCount))
 }
@@ -57,15 +96,82 @@ func (opts *GameOptions) NextVolume(delta int, volPercentages []int) {
 	PlaySound("cellClear")
 }
 
-func WaitForNGBoard(ctx context.Context, screen tcell.Screen, cfg DifficultyConfig) *Minesweeper {
+func (opts *GameOptions) NextNGBand(delta int) {
+	opts.ngBandIndex = (opts.ngBandIndex + delta + len(NGBandNames)) % len(NGBandNames)
+	opts.NGBand = NGBandNames[opts.ngBandIndex]
+}
+
+func (opts *GameOptions) NextBotStrategy(delta int) {
+	n := len(minesweeper.StrategyNames)
+	opts.botStrategyIndex = (opts.botStrategyIndex + delta + n) % n
+	opts.BotStrategy = minesweeper.StrategyNames[opts.botStrategyIndex]
+}
+
+func (opts *GameOptions) NextBotPace(delta int) {
+	opts.botPaceIndex = (opts.botPaceIndex + delta + len(botPaces)) % len(botPaces)
+	opts.BotPace = botPaces[opts.botPaceIndex]
+}
+
+// selectBackground, selectVolume, selectNGBand and the like pick a value,
+// as if it was chosen in the options menu. They fail on values the menu
+// doesn't offer.
+func (opts *GameOptions) selectBackground(name string) error {
+	i := slices.Index(backgroundNames(), name)
+	if i < 0 {
+		return fmt.Errorf("unknown background %q", name)
+	}
+	opts.bgIndex, opts.Background = i, name
+	return nil
+}
+
+func (opts *GameOptions) selectVolume(volume int) error {
+	i := slices.Index(volPercentages, volume)
+	if i < 0 {
+		return fmt.Errorf("volume %d is not one of %v", volume, volPercentages)
+	}
+	opts.volIndex, opts.Volume = i, volume
+	return nil
+}
+
+func (opts *GameOptions) selectNGBand(name string) error {
+	i := slices.Index(NGBandNames, name)
+	if i < 0 {
+		return fmt.Errorf("unknown NG difficulty %q", name)
+	}
+	opts.ngBandIndex, opts.NGBand = i, name
+	return nil
+}
+
+func (opts *GameOptions) selectBotStrategy(name string) error {
+	i := slices.Index(minesweeper.StrategyNames, name)
+	if i < 0 {
+		return fmt.Errorf("unknown bot strategy %q", name)
+	}
+	opts.botStrategyIndex, opts.BotStrategy = i, name
+	return nil
+}
+
+func (opts *GameOptions) selectBotPace(pace time.Duration) error {
+	i := slices.Index(botPaces, pace)
+	if i < 0 {
+		return fmt.Errorf("bot pace %v is not one of %v", pace, botPaces)
+	}
+	opts.botPaceIndex, opts.BotPace = i, pace
+	return nil
+}
+
+func WaitForNGBoard(ctx context.Context, screen tcell.Screen, cfg minesweeper.DifficultyConfig, seed int64, band minesweeper.DifficultyBand) *minesweeper.Minesweeper {
 	loadingMsg := "Generating NG board .."
 	spinnerTop := []string{" | ", "  /", "   ", "\\  "}
 	spinnerMid := []string{" | ", " / ", "---", " \\ "}
 	spinnerBot := []string{" | ", "/  ", "   ", "  \\"}
 	idx := 0
 	attempt := 0
+	// Last attempt count of each worker, shown as one spinning mark per worker
+	workerAttempts := make([]int, runtime.GOMAXPROCS(0))
+	workerMarks := []rune{'|', '/', '-', '\\'}
 
-	minesweeperCh, progressCh := GenerateNGBoard(ctx, cfg, TRIES, MAX_COMPONENT_SIZE)
+	minesweeperCh, progressCh := minesweeper.GenerateNGBoard(ctx, cfg, seed, TRIES, REPAIRS, MAX_COMPONENT_SIZE, band)
 	ticker := time.NewTicker(50 * time.Millisecond)
 	defer ticker.Stop()
 
@@ -87,9 +193,15 @@ func WaitForNGBoard(ctx context.Context, screen tcell.Screen, cfg DifficultyConf
 			return nil
 
 		// NG board generation is finished (either success OR failed)
-		case minesweeper := <-minesweeperCh:
+		case m, ok := <-minesweeperCh:
+			// Closed without a board -> cancelled, ctx.Done() handles it
+			if !ok {
+				minesweeperCh = nil
+				continue
+			}
+
 			// Failed -> show failed overlay
-			if minesweeper == nil {
+			if m == nil {
 				screen.Clear()
 				DrawOverlay(
 					screen, FailedOverlayStyle,
@@ -102,14 +214,16 @@ func WaitForNGBoard(ctx context.Context, screen tcell.Screen, cfg DifficultyConf
 				screen.Show()
 				time.Sleep(2000 * time.Millisecond)
 
-				minesweeper, err := GenerateBoardWithStartCell(cfg)
+				m, err := minesweeper.GenerateBoardWithStartCell(cfg, seed)
 				if err != nil {
 					log.Fatal(err)
 				}
-				return minesweeper
+				return m
 			}
 
-			// Success -> show success overlay
+			// Success -> show success overlay. Only this board is marked NG,
+			// the fallback above being a regular one.
+			m.NG = true
 			screen.Clear()
 			DrawOverlay(
 				screen, SuccessOverlayStyle,
@@ -121,18 +235,31 @@ func WaitForNGBoard(ctx context.Context, screen tcell.Screen, cfg DifficultyConf
 			)
 			screen.Show()
 			time.Sleep(2000 * time.Millisecond)
-			return minesweeper
+			return m
 
 		// Progress update from NG board generator
-		case attempt = <-progressCh:
+		case progress, ok := <-progressCh:
+			if !ok {
+				progressCh = nil
+				continue
+			}
+			attempt = max(attempt, progress.Total)
+			if progress.Worker < len(workerAttempts) {
+				workerAttempts[progress.Worker] = progress.Attempts
+			}
 
 		// Spinner tick overlay
 		case <-ticker.C:
+			marks := make([]rune, len(workerAttempts))
+			for i, n := range workerAttempts {
+				marks[i] = workerMarks[n%len(workerMarks)]
+			}
 			msgs := []string{
 				fmt.Sprintf("%s %s", strings.Repeat(" ", len(loadingMsg)), spinnerTop[idx%len(spinnerTop)]),
 				fmt.Sprintf("%s %s", loadingMsg, spinnerMid[idx%len(spinnerMid)]),
 				fmt.Sprintf("%s %s", strings.Repeat(" ", len(loadingMsg)), spinnerBot[idx%len(spinnerBot)]),
 				fmt.Sprintf("Attempt: %4d/%d", attempt, TRIES),
+				fmt.Sprintf("Workers: %s", string(marks)),
 				"",
 				"Press 'q' or 'Esc' key to cancel NG mode",
 			}
@@ -144,7 +271,219 @@ func WaitForNGBoard(ctx context.Context, screen tcell.Screen, cfg DifficultyConf
 	}
 }
 
-func RunGame(screen tcell.Screen, m *Minesweeper, opts *GameOptions, ng bool) GameState {
+// GenerateNGBoardCancellable runs WaitForNGBoard while listening to the
+// player, who can cancel with 'q' or Esc. It returns nil when cancelled.
+func GenerateNGBoardCancellable(screen tcell.Screen, cfg minesweeper.DifficultyConfig, seed int64, band minesweeper.DifficultyBand) *minesweeper.Minesweeper {
+	// Create a cancellable context for NG board generation.
+	// cancel() can be called explicitly (when user presses
+	// 'q') or at the end (via defer).
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel() // always call cancel eventually (avoid context leak)
+
+	// Channel to receive the NG board generation result
+	doneCh := make(chan *minesweeper.Minesweeper, 1)
+
+	// Run NG board generation in a goroutine
+	go func() {
+		doneCh <- WaitForNGBoard(ctx, screen, cfg, seed, band)
+	}()
+
+	for {
+		select {
+		case ev := <-screenEventCh:
+			switch ev := ev.(type) {
+			case *tcell.EventKey:
+				// User cancels NG board generation with 'q' or Esc
+				if (ev.Key() == tcell.KeyRune && ev.Rune() == 'q') || ev.Key() == tcell.KeyEsc {
+					cancel() // triggers ctx.Done() inside WaitForNGBoard
+				}
+			case *tcell.EventResize:
+				screen.Sync()
+			}
+
+		// NG board generation finishes (either success OR failed)
+		case m := <-doneCh:
+			return m
+		}
+	}
+}
+
+// revealCell reveals a cell (or chords a revealed one) and plays the
+// sound matching the outcome
+func revealCell(m *minesweeper.Minesweeper, row, col int) {
+	if ok := m.Play(minesweeper.ActionReveal, row, col); ok {
+		playRevealSound(m)
+	}
+}
+
+func chordCell(m *minesweeper.Minesweeper, row, col int) {
+	if ok := m.Play(minesweeper.ActionChord, row, col); ok {
+		playRevealSound(m)
+	}
+}
+
+func flagCell(m *minesweeper.Minesweeper, row, col int) {
+	m.Play(minesweeper.ActionFlag, row, col)
+}
+
+func undoMove(m *minesweeper.Minesweeper) {
+	if ok := m.Play(minesweeper.ActionUndo, 0, 0); ok {
+		StopAllSounds()
+		PlaySound("cellClear")
+	}
+}
+
+func redoMove(m *minesweeper.Minesweeper) {
+	if ok := m.Play(minesweeper.ActionRedo, 0, 0); ok {
+		playRevealSound(m)
+	}
+}
+
+func playRevealSound(m *minesweeper.Minesweeper) {
+	if m.IsGameOver {
+		if m.IsWon {
+			PlaySound("win")
+		} else {
+			PlaySound("bomb")
+		}
+	} else {
+		PlaySound("cellClear")
+	}
+}
+
+// recordFinishedGame keeps the replay of a finished game and, unless it's
+// unranked, adds it to the statistics
+func recordFinishedGame(screen tcell.Screen, m *minesweeper.Minesweeper) {
+	if err := SaveLastReplay(m); err != nil {
+		ShowOverlay(
+			screen, FailedOverlayStyle,
+			[]string{
+				"Failed to save the replay!😭",
+				err.Error(),
+			},
+		)
+	}
+	if m.Unranked {
+		return
+	}
+	if err := RecordGame(m); err != nil {
+		ShowOverlay(
+			screen, FailedOverlayStyle,
+			[]string{
+				"Failed to record the statistics!😭",
+				err.Error(),
+			},
+		)
+	}
+}
+
+// qualifiesForHighScore reports whether a win makes it into the high
+// scores, wins using hints never doing
+func qualifiesForHighScore(m *minesweeper.Minesweeper) bool {
+	if m.HintsUsed > 0 {
+		return false
+	}
+	hs, err := LoadHighScores()
+	if err != nil {
+		return false
+	}
+	category := StatsCategory(minesweeper.DifficultyConfig{Rows: m.Rows, Cols: m.Cols, BombCount: m.BombCount}, m.NG)
+	return hs.Qualifies(category, m.ElapsedTime())
+}
+
+func addHighScore(screen tcell.Screen, m *minesweeper.Minesweeper, a minesweeper.Analysis, name string) {
+	hs, err := LoadHighScores()
+	if err == nil {
+		category := StatsCategory(minesweeper.DifficultyConfig{Rows: m.Rows, Cols: m.Cols, BombCount: m.BombCount}, m.NG)
+		hs.Add(category, HighScore{
+			Name:             name,
+			TimeMs:           m.ElapsedTime().Milliseconds(),
+			ThreeBVPerSecond: ThreeBVPerSecond(m, a),
+			Date:             time.Now(),
+			Seed:             m.Seed,
+		})
+		err = SaveHighScores(hs)
+	}
+	if err != nil {
+		ShowOverlay(
+			screen, FailedOverlayStyle,
+			[]string{
+				"Failed to save the high score!😭",
+				err.Error(),
+			},
+		)
+	}
+}
+
+func exportBoard(screen tcell.Screen, m *minesweeper.Minesweeper) {
+	path, err := ExportBoard(m)
+	if err != nil {
+		ShowOverlay(
+			screen, FailedOverlayStyle,
+			[]string{
+				"Failed to export the board!😭",
+				err.Error(),
+			},
+		)
+		return
+	}
+	ShowOverlay(
+		screen, SuccessOverlayStyle,
+		[]string{
+			"Board exported!😎",
+			path,
+		},
+	)
+}
+
+// HintExplanation is the reasoning behind a hint, shown beside the board
+// with the numbers it relies on highlighted
+type HintExplanation struct {
+	Title      string
+	Lines      []string
+	Supporting [][2]int
+}
+
+func explainHint(m *minesweeper.Minesweeper, hint minesweeper.Hint) HintExplanation {
+	cell := fmt.Sprintf("%d,%d", hint.Position[0], hint.Position[1])
+	if !hint.Safe {
+		return HintExplanation{
+			Title: fmt.Sprintf("Why guess %s?", cell),
+			Lines: []string{
+				"No cell can be proven safe, this one",
+				fmt.Sprintf("is the least likely bomb: %.0f%%.", hint.Probability*100),
+			},
+		}
+	}
+	if m.RevealedCount == 0 {
+		return HintExplanation{
+			Title: fmt.Sprintf("Why is %s safe?", cell),
+			Lines: []string{"The start cell never holds a bomb."},
+		}
+	}
+
+	step, ok := m.Explain(hint.Position, MAX_COMPONENT_SIZE)
+	if !ok {
+		return HintExplanation{
+			Title: fmt.Sprintf("Why is %s safe?", cell),
+			Lines: []string{"The solver can't tell anymore."},
+		}
+	}
+	e := HintExplanation{
+		Title: fmt.Sprintf("Why is %s safe? (%s)", cell, step.Kind),
+		Lines: step.Lines(),
+	}
+	for _, c := range step.Constraints {
+		e.Supporting = append(e.Supporting, c.Position)
+	}
+	return e
+}
+
+func drawGameHelpHint(screen tcell.Screen, opts *GameOptions) {
+	DrawHelpHint(screen, opts.Style, "Arrows/wasd/hjkl = move, Tab = next, Space = reveal, f = flag, c = chord, ? = hint, x = explain, u/U = undo/redo, e = export, r = new, q = quit")
+}
+
+func RunGame(screen tcell.Screen, m *minesweeper.Minesweeper, opts *GameOptions, ng bool) GameState {
 	var err error
 
 	screen.EnableMouse(tcell.MouseButtonEvents, tcell.MouseDragEvents)
@@ -152,14 +491,83 @@ func RunGame(screen tcell.Screen, m *Minesweeper, opts *GameOptions, ng bool) Ga
 
 	StopAllSounds()
 
+	// A resumed game picks up its clock where it was left
+	if m.IsStarted() && !m.IsGameOver {
+		m.StartClock()
+	}
+
+	// Redraw on a timer so the HUD clock keeps ticking without input
+	ticker := time.NewTicker(100 * time.Millisecond)
+	defer ticker.Stop()
+
+	cursor := NewCursor(m)
+	var hint *minesweeper.Hint
+	// Reasoning behind the hint, shown until the hint goes away
+	var explanation *HintExplanation
+	// Analysis of the board, computed once it's won
+	var analysis *minesweeper.Analysis
+	// Whether the end of the game got recorded
+	finishRecorded := false
+	// A loss in practice mode can still be undone, it's only recorded
+	// once the player moves on to a new board or leaves
+	lossPending := false
+	recordPendingLoss := func() {
+		if lossPending {
+			lossPending = false
+			recordFinishedGame(screen, m)
+		}
+	}
+	// Name entry after a win making it into the high scores
+	enteringName := false
+	nameBuffer := ""
+
 	playing := true
 	ox, oy := -1, -1
 	var lastMouseButtons tcell.ButtonMask
 	for playing {
+		// Record the game as soon as it's over, practice losses aside (see
+		// lossPending)
+		if !m.IsGameOver {
+			finishRecorded = false
+			lossPending = false
+		} else if !finishRecorded {
+			finishRecorded = true
+			if m.IsWon {
+				a := m.Analyze(MAX_COMPONENT_SIZE)
+				analysis = &a
+			}
+			if m.Practice && !m.IsWon {
+				lossPending = true
+			} else {
+				recordFinishedGame(screen, m)
+			}
+			if m.IsWon && !m.Unranked && qualifiesForHighScore(m) {
+				enteringName = true
+				nameBuffer = opts.PlayerName
+			}
+		}
+
 		screen.Clear()
 		DrawBackground(screen, opts.Background, m.IsGameOver && !m.IsWon)
-		m.Draw(screen, opts.BorderStyle, opts.ShowInnerBorders)
-		m.DrawSmiley(screen, opts.Style, opts.ShowInnerBorders, lastMouseButtons)
+		DrawBoard(screen, m, opts.BorderStyle, opts.ShowInnerBorders)
+		DrawCursor(screen, m, cursor, opts.ShowInnerBorders)
+		if hint != nil {
+			DrawHint(screen, m, *hint, opts.Style, opts.ShowInnerBorders)
+			if explanation != nil {
+				DrawExplanation(screen, m, *explanation, opts.Style, opts.ShowInnerBorders)
+			}
+		}
+		namePrompt := ""
+		if enteringName {
+			namePrompt = fmt.Sprintf("New high score! Name: %s_ (Enter = save, Esc = skip)", nameBuffer)
+		}
+		DrawSmiley(screen, m, opts.Style, opts.ShowInnerBorders, lastMouseButtons, namePrompt)
+		DrawHUD(screen, m, opts.ShowInnerBorders)
+		DrawSeed(screen, m, opts.Style, opts.ShowInnerBorders)
+		if m.IsWon && analysis != nil {
+			DrawWinStats(screen, m, *analysis, opts.Style, opts.ShowInnerBorders)
+		}
+		drawGameHelpHint(screen, opts)
 		screen.Show()
 
 		select {
@@ -168,59 +576,104 @@ func RunGame(screen tcell.Screen, m *Minesweeper, opts *GameOptions, ng bool) Ga
 			case *tcell.EventResize:
 				screen.Sync()
 			case *tcell.EventKey:
+				if enteringName {
+					switch ev.Key() {
+					case tcell.KeyEnter:
+						name := strings.TrimSpace(nameBuffer)
+						if name == "" {
+							name = "Anonymous"
+						}
+						if name != opts.PlayerName {
+							opts.PlayerName = name
+							saveOptions(screen, opts)
+						}
+						addHighScore(screen, m, *analysis, name)
+						enteringName = false
+					case tcell.KeyEsc:
+						enteringName = false
+					case tcell.KeyBackspace, tcell.KeyBackspace2:
+						if runes := []rune(nameBuffer); len(runes) > 0 {
+							nameBuffer = string(runes[:len(runes)-1])
+						}
+					case tcell.KeyRune:
+						if len([]rune(nameBuffer)) < MAX_NAME_LENGTH && unicode.IsPrint(ev.Rune()) {
+							nameBuffer += string(ev.Rune())
+						}
+					}
+					break
+				}
+				if cursor.HandleKey(ev, m) {
+					break
+				}
+
 				switch ev.Key() {
 				case tcell.KeyEsc:
-					return StateMenu
+					playing = false
+				case tcell.KeyEnter:
+					revealCell(m, cursor.Row, cursor.Col)
+					hint = nil
+				case tcell.KeyCtrlZ:
+					undoMove(m)
+					hint = nil
+				case tcell.KeyCtrlY:
+					redoMove(m)
+					hint = nil
 				case tcell.KeyRune:
 					switch ev.Rune() {
+					case ' ':
+						revealCell(m, cursor.Row, cursor.Col)
+						hint = nil
+					case 'f':
+						flagCell(m, cursor.Row, cursor.Col)
+						hint = nil
+					case 'c':
+						chordCell(m, cursor.Row, cursor.Col)
+						hint = nil
+					case 'u':
+						undoMove(m)
+						hint = nil
+					case 'U':
+						redoMove(m)
+						hint = nil
+					case '?':
+						if h, ok := m.Hint(MAX_COMPONENT_SIZE); ok {
+							hint = &h
+							explanation = nil
+							m.HintsUsed++
+							cursor.Row, cursor.Col = h.Position[0], h.Position[1]
+						}
+					case 'x':
+						// Toggles the reasoning behind the hint shown
+						if hint != nil && explanation == nil {
+							e := explainHint(m, *hint)
+							explanation = &e
+						} else {
+							explanation = nil
+						}
+					case 'e':
+						exportBoard(screen, m)
 					case 'q':
 						playing = false
 					case 'r':
 						StopAllSounds()
+						recordPendingLoss()
 						if ng {
-							// Create a cancellable context for NG board generation.
-							// cancel() can be called explicitly (when user presses
-							// 'q') or at the end (via defer).
-							ctx, cancel := context.WithCancel(context.Background())
-							defer cancel() // always call cancel eventually (avoid context leak)
-
-							// Channel to receive the NG board generation result
-							doneCh := make(chan *Minesweeper, 1)
-
-							// Run NG board generation in a goroutine
-							go func() {
-								doneCh <- WaitForNGBoard(ctx, screen, opts.Difficulty)
-							}()
-
-							regenerating := true
-							for regenerating {
-								select {
-								case regEv := <-screenEventCh:
-									switch regEv := regEv.(type) {
-									case *tcell.EventKey:
-										// User cancels NG board generation with 'q' or Esc
-										if regEv.Rune() == 'q' || regEv.Key() == tcell.KeyEsc {
-											cancel() // triggers ctx.Done() inside WaitForNGBoard
-										}
-									}
-
-								// NG board generation finishes (either success OR failed)
-								case newM := <-doneCh:
-									m = newM
-									regenerating = false
-								}
-							}
-
+							m = GenerateNGBoardCancellable(screen, opts.Difficulty, minesweeper.NewSeed(), NGBands[opts.NGBand])
 							// If NG board generation is cancelled, go back to main menu
 							if m == nil {
 								return StateMenu
 							}
 						} else {
-							m, err = GenerateBoardWithStartCell(opts.Difficulty)
+							m, err = minesweeper.GenerateBoardWithStartCell(opts.Difficulty, minesweeper.NewSeed())
 						}
 						if err != nil {
 							log.Fatal(err)
 						}
+						m.Practice = opts.Practice
+						cursor = NewCursor(m)
+						hint = nil
+						analysis = nil
+						finishRecorded = false
 					}
 				}
 			case *tcell.EventMouse:
@@ -235,23 +688,15 @@ func RunGame(screen tcell.Screen, m *Minesweeper, opts *GameOptions, ng bool) Ga
 					}
 				case tcell.ButtonNone:
 					if ox >= 0 {
-						row, col, ok := m.ScreenToGrid(screen, x, y, opts.ShowInnerBorders)
+						row, col, ok := ScreenToGrid(screen, m, x, y, opts.ShowInnerBorders)
 						if ok {
+							cursor.Row, cursor.Col = row, col
+							hint = nil
 							switch lastMouseButtons {
 							case tcell.Button1:
-								if ok := m.Reveal(row, col, true); ok {
-									if m.IsGameOver {
-										if m.IsWon {
-											PlaySound("win")
-										} else {
-											PlaySound("bomb")
-										}
-									} else {
-										PlaySound("cellClear")
-									}
-								}
+								revealCell(m, row, col)
 							case tcell.Button2:
-								m.Flag(row, col)
+								flagCell(m, row, col)
 							}
 						}
 						ox, oy = -1, -1
@@ -259,7 +704,23 @@ func RunGame(screen tcell.Screen, m *Minesweeper, opts *GameOptions, ng bool) Ga
 					}
 				}
 			}
-		default:
+		case <-ticker.C:
+		}
+	}
+
+	recordPendingLoss()
+
+	// Keep an unfinished game around so it can be continued later
+	if m.IsStarted() && !m.IsGameOver {
+		m.StopClock()
+		if err := SaveGame(m); err != nil {
+			ShowOverlay(
+				screen, FailedOverlayStyle,
+				[]string{
+					"Failed to save the game!😭",
+					err.Error(),
+				},
+			)
 		}
 	}
 
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"time"
+)
+
+const (
+	// HIGHSCORES_VERSION is bumped whenever the high-score file layout
+	// changes, older files being migrated on load
+	HIGHSCORES_VERSION = 1
+	HIGHSCORES_SIZE    = 10
+	MAX_NAME_LENGTH    = 16
+)
+
+type HighScore struct {
+	Name             string    `json:"name"`
+	TimeMs           int64     `json:"timeMs"`
+	ThreeBVPerSecond float64   `json:"threeBVPerSecond"`
+	Date             time.Time `json:"date"`
+	Seed             int64     `json:"seed"`
+}
+
+// HighScores holds the fastest wins, per category (see StatsCategory)
+type HighScores struct {
+	Version int                    `json:"version"`
+	Tables  map[string][]HighScore `json:"tables"`
+}
+
+var ErrCorruptHighScores = errors.New("high-score file is corrupt")
+
+func NewHighScores() *HighScores {
+	return &HighScores{
+		Version: HIGHSCORES_VERSION,
+		Tables:  make(map[string][]HighScore),
+	}
+}
+
+// CategoryNames lists the preset categories first, then the custom
+// ones having any score
+func (hs *HighScores) CategoryNames() []string {
+	return categoryNames(maps.Keys(hs.Tables))
+}
+
+// Qualifies reports whether a win in the given time makes it into the
+// table of its category
+func (hs *HighScores) Qualifies(category string, elapsed time.Duration) bool {
+	table := hs.Tables[category]
+	return len(table) < HIGHSCORES_SIZE || elapsed.Milliseconds() < table[len(table)-1].TimeMs
+}
+
+// Add inserts a score in the table of its category and returns its rank,
+// starting at 1, or 0 when it didn't make it
+func (hs *HighScores) Add(category string, score HighScore) int {
+	table := append(hs.Tables[category], score)
+	// Ties go to the earliest win
+	slices.SortStableFunc(table, func(a, b HighScore) int {
+		if c := cmp.Compare(a.TimeMs, b.TimeMs); c != 0 {
+			return c
+		}
+		return a.Date.Compare(b.Date)
+	})
+	if len(table) > HIGHSCORES_SIZE {
+		table = table[:HIGHSCORES_SIZE]
+	}
+	hs.Tables[category] = table
+
+	for i := range table {
+		if table[i] == score {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+// LoadHighScores reads the high-score file, an absent file meaning no
+// score yet. Files written by a newer version are refused rather than
+// overwritten.
+func LoadHighScores() (*HighScores, error) {
+	path, err := dataPath(HIGHSCORES_FILE_NAME)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return NewHighScores(), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	hs := NewHighScores()
+	if err := json.Unmarshal(data, hs); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrCorruptHighScores, err)
+	}
+	if hs.Version < 1 || hs.Version > HIGHSCORES_VERSION {
+		return nil, fmt.Errorf("%w: got version %d, expected at most %d", ErrCorruptHighScores, hs.Version, HIGHSCORES_VERSION)
+	}
+	hs.Version = HIGHSCORES_VERSION
+	if hs.Tables == nil {
+		hs.Tables = make(map[string][]HighScore)
+	}
+
+	return hs, nil
+}
+
+func SaveHighScores(hs *HighScores) error {
+	path, err := dataPath(HIGHSCORES_FILE_NAME)
+	if err != nil {
+		return err
+	}
+
+	return writeFileAtomic(path, func(f *os.File) error {
+		enc := json.NewEncoder(f)
+		enc.SetIndent("", "  ")
+		return enc.Encode(hs)
+	})
+}
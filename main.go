@@ -1,9 +1,12 @@
 package main
 
 import (
-	"context"
+	"errors"
+	"flag"
 	"log"
+	"os"
 
+	"github.com/ahmadnaufalhakim/go-minesweeper/minesweeper"
 	"github.com/gdamore/tcell/v2"
 )
 
@@ -12,18 +15,41 @@ type GameState int
 const (
 	StateMenu GameState = iota
 	StatePlaying
+	StateContinue
+	StateReplay
+	StateWatchBot
 	StateQuit
 	gameStateCount
 )
 
 const (
 	TRIES              = 1500
+	REPAIRS            = 100
 	MAX_COMPONENT_SIZE = 18
 )
 
 var screenEventCh chan tcell.Event
 
 func main() {
+	// Subcommands run without the TUI
+	if len(os.Args) > 1 {
+		if command, ok := commands[os.Args[1]]; ok {
+			os.Exit(command.run(os.Args[2:]))
+		}
+	}
+
+	gameOptions, configErr := LoadOptions()
+	if configErr != nil {
+		gameOptions = NewGameOptions()
+	}
+	launch, err := parseLaunchFlags(os.Args[1:], gameOptions)
+	if errors.Is(err, flag.ErrHelp) {
+		return
+	}
+	if err != nil {
+		os.Exit(2)
+	}
+
 	// Initialize screen
 	screen, err := tcell.NewScreen()
 	if err != nil {
@@ -55,64 +81,99 @@ func main() {
 
 	screen.SetStyle(DefaultStyle)
 
-	gameOptions := NewGameOptions()
+	if configErr != nil {
+		ShowOverlay(
+			screen, DefaultOverlayStyle,
+			[]string{
+				"Invalid config file, using the default options!😮",
+				configErr.Error(),
+			},
+		)
+	}
+	if launch.options {
+		saveOptions(screen, gameOptions)
+	}
 	InitSoundSystem(gameOptions)
 
 	for {
-		state, gameOptions, cfg, ng := RunMenu(screen, gameOptions)
+		var state GameState
+		var cfg minesweeper.DifficultyConfig
+		var seed int64
+		var ng bool
+		if launch.imported != nil {
+			// Imported boards may be hand-designed, they don't count in
+			// the stats and high scores
+			m := launch.imported
+			m.Unranked = true
+			m.Practice = gameOptions.Practice
+			gameOptions.Difficulty = minesweeper.DifficultyConfig{Rows: m.Rows, Cols: m.Cols, BombCount: m.BombCount}
+			launch.imported = nil
+			RunGame(screen, m, gameOptions, false)
+			continue
+		}
+		if launch.board {
+			// Board flags skip the menu, for the first game only
+			state, cfg, seed, ng = StatePlaying, launch.cfg, launch.seed, launch.ng
+			gameOptions.Difficulty = cfg
+			launch.board = false
+		} else {
+			state, gameOptions, cfg, seed, ng = RunMenu(screen, gameOptions)
+		}
 		if state == StateQuit {
 			break
 		}
+		if state == StateContinue {
+			board, err := LoadSavedGame()
+			if err != nil {
+				ShowOverlay(
+					screen, FailedOverlayStyle,
+					[]string{
+						"Failed to load the saved game!😭",
+						err.Error(),
+					},
+				)
+				continue
+			}
+
+			RunGame(screen, board, gameOptions, board.NG)
+		}
+		if state == StateReplay {
+			replay, err := LoadLastReplay()
+			if err != nil {
+				ShowOverlay(
+					screen, FailedOverlayStyle,
+					[]string{
+						"Failed to load the replay!😭",
+						err.Error(),
+					},
+				)
+				continue
+			}
+
+			RunReplay(screen, replay, gameOptions)
+		}
+		if state == StateWatchBot {
+			RunBot(screen, cfg, seed, gameOptions)
+		}
 		if state == StatePlaying {
-			var minesweeper *Minesweeper
+			var board *minesweeper.Minesweeper
 			if ng {
-				// Create a cancellable context for NG board generation.
-				// cancel() can be called explicitly (when user presses
-				// 'q') or at the end (via defer).
-				ctx, cancel := context.WithCancel(context.Background())
-				defer cancel() // always call cancel eventually (avoid context leak)
-
-				// Channel to receive the NG board generation result
-				doneCh := make(chan *Minesweeper, 1)
-
-				// Run NG board generation in a goroutine
-				go func() {
-					doneCh <- WaitForNGBoard(ctx, screen, cfg)
-				}()
-
-				generating := true
-				for generating {
-					select {
-					case ev := <-screenEventCh:
-						switch ev := ev.(type) {
-						case *tcell.EventKey:
-							// User cancels NG board generation with 'q' or Esc
-							if (ev.Key() == tcell.KeyRune && ev.Rune() == 'q') || ev.Key() == tcell.KeyEsc {
-								cancel() // triggers ctx.Done() inside WaitForNGBoard
-							}
-						case *tcell.EventResize:
-							screen.Sync()
-						}
-
-					// NG board generation finishes (either success OR failed)
-					case m := <-doneCh:
-						minesweeper = m
-						generating = false
-					}
-				}
-
+				board = GenerateNGBoardCancellable(screen, cfg, seed, NGBands[gameOptions.NGBand])
 				// If NG board generation is cancelled, go back to main menu
-				if minesweeper == nil {
+				if board == nil {
 					continue
 				}
 			} else {
-				minesweeper, err = GenerateBoardWithStartCell(cfg)
+				board, err = minesweeper.GenerateBoardWithStartCell(cfg, seed)
 				if err != nil {
 					log.Fatal(err)
 				}
 			}
+			// New games take practice mode from the options, continued ones
+			// keep the one they were saved with
+			board.Practice = gameOptions.Practice
 
-			RunGame(screen, minesweeper, gameOptions, ng)
+			RunGame(screen, board, gameOptions, ng)
 		}
 	}
 }
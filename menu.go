@@ -2,11 +2,14 @@ package main
 
 import (
 	"fmt"
+	"slices"
 	"strconv"
 	"strings"
+	"time"
 	"unicode"
 
 	"github.com/ahmadnaufalhakim/go-minesweeper/assets"
+	"github.com/ahmadnaufalhakim/go-minesweeper/minesweeper"
 	"github.com/gdamore/tcell/v2"
 )
 
@@ -18,9 +21,44 @@ const (
 	PageCredits
 	PageQuitConfirm
 	PageCustomInput
+	PageStatistics
+	PageHighScores
 	menuPageCount
 )
 
+type MainMenuItem int
+
+const (
+	MainItemContinue MainMenuItem = iota
+	MainItemPlay
+	MainItemPlayNG
+	MainItemWatchBot
+	MainItemReplay
+	MainItemOptions
+	MainItemStatistics
+	MainItemHighScores
+	MainItemCredits
+	MainItemQuit
+	mainMenuItemCount
+)
+
+// mainMenuItems lists the main menu entries, "Continue" is only shown
+// when there's a saved game to restore and "Watch last replay" when a
+// game was finished before
+func mainMenuItems(hasSavedGame, hasReplay bool) []MainMenuItem {
+	items := make([]MainMenuItem, 0, mainMenuItemCount)
+	for item := range mainMenuItemCount {
+		if item == MainItemContinue && !hasSavedGame {
+			continue
+		}
+		if item == MainItemReplay && !hasReplay {
+			continue
+		}
+		items = append(items, item)
+	}
+	return items
+}
+
 func drawTitleItems(
 	screen tcell.Screen,
 	titleItems []string,
@@ -52,19 +90,38 @@ func drawMenuItems(
 
 func drawMainMenu(
 	screen tcell.Screen, titleItems []string,
-	selected int, difficulty string, difficultyNG string,
+	selected int, items []MainMenuItem,
+	difficulty string, difficultyNG string,
 	opts *GameOptions,
 ) int {
 	w, h := screen.Size()
 
 	titleHeight := len(titleItems)
 
-	menuItems := []string{
-		fmt.Sprintf("Play <%s>", strings.Repeat(" ", len(difficulty))),
-		fmt.Sprintf("Play NG <%s>", strings.Repeat(" ", len(difficultyNG))),
-		"Options",
-		"Credits",
-		"Quit",
+	menuItems := make([]string, len(items))
+	for i, item := range items {
+		switch item {
+		case MainItemContinue:
+			menuItems[i] = "Continue"
+		case MainItemPlay:
+			menuItems[i] = fmt.Sprintf("Play <%s>", strings.Repeat(" ", len(difficulty)))
+		case MainItemPlayNG:
+			menuItems[i] = fmt.Sprintf("Play NG <%s>", strings.Repeat(" ", len(difficultyNG)))
+		case MainItemWatchBot:
+			menuItems[i] = fmt.Sprintf("Watch bot <%s>", opts.BotStrategy)
+		case MainItemReplay:
+			menuItems[i] = "Watch last replay"
+		case MainItemOptions:
+			menuItems[i] = "Options"
+		case MainItemStatistics:
+			menuItems[i] = "Statistics"
+		case MainItemHighScores:
+			menuItems[i] = "High scores"
+		case MainItemCredits:
+			menuItems[i] = "Credits"
+		case MainItemQuit:
+			menuItems[i] = "Quit"
+		}
 	}
 	menuHeight := (len(menuItems)+1)*2 - 1
 
@@ -74,12 +131,21 @@ func drawMainMenu(
 	drawTitleItems(screen, titleItems, titleOffsetY, opts)
 	drawMenuItems(screen, selected, "⚑⚑⚑ Main Menu  ⚑⚑⚑", menuItems, titleOffsetY+titleHeight+4, opts)
 
-	x := ((w-len(menuItems[0]))/2 - len(menuItems[0])%2) + (len(menuItems[0]) - (len(difficulty) + 1))
-	y := titleOffsetY + titleHeight + 6
-	DrawString(screen, x, y, DifficultyToStyle[difficulty], difficulty)
-	xNG := ((w-len(menuItems[1]))/2 - len(menuItems[1])%2) + (len(menuItems[1]) - (len(difficultyNG) + 1))
-	yNG := titleOffsetY + titleHeight + 8
-	DrawString(screen, xNG, yNG, DifficultyToStyle[difficultyNG], difficultyNG)
+	// Draw the chosen difficulties inside the "Play" entries
+	for i, item := range items {
+		var label string
+		switch item {
+		case MainItemPlay:
+			label = difficulty
+		case MainItemPlayNG:
+			label = difficultyNG
+		default:
+			continue
+		}
+		x := ((w-len(menuItems[i]))/2 - len(menuItems[i])%2) + (len(menuItems[i]) - (len(label) + 1))
+		y := titleOffsetY + titleHeight + 6 + i*2
+		DrawString(screen, x, y, DifficultyToStyle[label], label)
+	}
 
 	return len(menuItems)
 }
@@ -98,6 +164,9 @@ func drawOptionsMenu(
 		fmt.Sprintf("Border style: <%v>", opts.BorderStyle),
 		fmt.Sprintf("Background: <%v>", opts.Background),
 		fmt.Sprintf("Volume: <%v>", opts.Volume),
+		fmt.Sprintf("NG difficulty: <%v>", opts.NGBand),
+		fmt.Sprintf("Practice mode: <%v>", opts.Practice),
+		fmt.Sprintf("Bot pace: <%v>", opts.BotPace),
 		"Back",
 	}
 	menuHeight := (len(menuItems)+1)*2 - 1
@@ -132,6 +201,119 @@ func drawCredits(
 	drawMenuItems(screen, -1, "⚑⚑⚑ Credits  ⚑⚑⚑", menuItems, titleOffsetY+titleHeight+2, opts)
 }
 
+func drawStatistics(
+	screen tcell.Screen,
+	selected int,
+	category string, c *CategoryStats,
+	message string,
+	opts *GameOptions,
+) int {
+	_, h := screen.Size()
+
+	menuItems := []string{
+		fmt.Sprintf("Category: <%s>", category),
+		"Reset category",
+		"Back",
+	}
+	lines := statisticsLines(c)
+
+	contentHeight := 2 + len(menuItems)*2 + 1 + len(lines) + 2
+	offsetY := (h-contentHeight)/2 - contentHeight%2
+
+	drawMenuItems(screen, selected, "⚑⚑⚑ Statistics  ⚑⚑⚑", menuItems, offsetY, opts)
+	linesOffsetY := offsetY + 2 + len(menuItems)*2 + 1
+	for i, line := range lines {
+		DrawCentered(screen, linesOffsetY+i, opts.Style, line)
+	}
+	if message != "" {
+		DrawCentered(screen, linesOffsetY+len(lines)+1, opts.Style, message)
+	}
+
+	return len(menuItems)
+}
+
+// statisticsLines lays out the stats of a category, the histogram lines
+// all being of the same length so they line up once centered
+func statisticsLines(c *CategoryStats) []string {
+	lines := []string{
+		fmt.Sprintf("Played: %d   Wins: %d   Losses: %d   Win rate: %.1f%%", c.Played(), c.Wins, c.Losses, c.WinRate()),
+		fmt.Sprintf("Wins with hints: %d   Current streak: %d   Best streak: %d", c.HintedWins, c.CurrentStreak, c.BestStreak),
+		"",
+		"Best times",
+	}
+	switch {
+	case c.Wins == 0:
+		lines = append(lines, "No wins yet")
+	case len(c.BestTimesMs) == 0:
+		lines = append(lines, "No wins without hints yet")
+	}
+	for i, ms := range c.BestTimesMs {
+		lines = append(lines, fmt.Sprintf("%d. %8.2fs", i+1, float64(ms)/1000))
+	}
+
+	lines = append(lines, "", "Win times")
+	maxCount := slices.Max(c.Histogram)
+	boundLabel := func(d time.Duration) string {
+		if d < time.Minute {
+			return fmt.Sprintf("%ds", int(d.Seconds()))
+		}
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	}
+	for i, count := range c.Histogram {
+		label := ">= " + boundLabel(histogramBounds[len(histogramBounds)-1])
+		if i < len(histogramBounds) {
+			label = "< " + boundLabel(histogramBounds[i])
+		}
+		bar := ""
+		if maxCount > 0 {
+			bar = strings.Repeat("#", count*30/maxCount)
+		}
+		lines = append(lines, fmt.Sprintf("%-6s %-30s %4d", label, bar, count))
+	}
+
+	return lines
+}
+
+func drawHighScores(
+	screen tcell.Screen,
+	selected int,
+	category string, table []HighScore,
+	message string,
+	opts *GameOptions,
+) int {
+	_, h := screen.Size()
+
+	menuItems := []string{
+		fmt.Sprintf("Category: <%s>", category),
+		"Back",
+	}
+	lines := []string{fmt.Sprintf("%-4s %-*s %9s %6s %-10s %10s", "Rank", MAX_NAME_LENGTH, "Name", "Time", "3BV/s", "Date", "Seed")}
+	for i, score := range table {
+		lines = append(lines, fmt.Sprintf(
+			"%-4d %-*s %8.2fs %6.2f %-10s %10d",
+			i+1, MAX_NAME_LENGTH, score.Name, float64(score.TimeMs)/1000, score.ThreeBVPerSecond,
+			score.Date.Format(time.DateOnly), score.Seed,
+		))
+	}
+	if len(table) == 0 {
+		lines = append(lines, "", "No high score yet")
+	}
+
+	contentHeight := 2 + len(menuItems)*2 + 1 + len(lines) + 2
+	offsetY := (h-contentHeight)/2 - contentHeight%2
+
+	drawMenuItems(screen, selected, "⚑⚑⚑ High scores  ⚑⚑⚑", menuItems, offsetY, opts)
+	linesOffsetY := offsetY + 2 + len(menuItems)*2 + 1
+	for i, line := range lines {
+		DrawCentered(screen, linesOffsetY+i, opts.Style, line)
+	}
+	if message != "" {
+		DrawCentered(screen, linesOffsetY+len(lines)+1, opts.Style, message)
+	}
+
+	return len(menuItems)
+}
+
 func drawQuitConfirm(screen tcell.Screen, opts *GameOptions) {
 	_, h := screen.Size()
 
@@ -152,7 +334,8 @@ func drawQuitConfirm(screen tcell.Screen, opts *GameOptions) {
 func drawCustomInput(
 	screen tcell.Screen, titleItems []string,
 	selected int,
-	cfg DifficultyConfig,
+	cfg minesweeper.DifficultyConfig,
+	seed int64,
 	buf string, errMsg string,
 	opts *GameOptions,
 ) int {
@@ -160,10 +343,16 @@ func drawCustomInput(
 
 	titleHeight := len(titleItems)
 
+	seedItem := "Seed: random"
+	if seed >= 0 {
+		seedItem = fmt.Sprintf("Seed: %d", seed)
+	}
+
 	menuItems := []string{
 		fmt.Sprintf("Rows: <%d>", cfg.Rows),
 		fmt.Sprintf("Cols: <%d>", cfg.Cols),
 		fmt.Sprintf("BombCount: %d", cfg.BombCount),
+		seedItem,
 		"Start",
 		"Back",
 	}
@@ -175,7 +364,7 @@ func drawCustomInput(
 	drawTitleItems(screen, titleItems, titleOffsetY, opts)
 	drawMenuItems(screen, selected, "⚑⚑⚑ Custom Difficulty  ⚑⚑⚑", menuItems, titleOffsetY+titleHeight+2, opts)
 
-	if selected == 2 {
+	if selected == 2 || selected == 3 {
 		DrawCentered(screen, titleOffsetY+titleHeight+2+(len(menuItems)+1)*2, opts.Style, fmt.Sprintf("Typing: %s", buf))
 	}
 	if errMsg != "" {
@@ -186,9 +375,18 @@ func drawCustomInput(
 }
 
 func drawHelpHint(screen tcell.Screen, opts *GameOptions) {
-	w, h := screen.Size()
-	message := "W/S = up/down, A/D = change, Enter = select, Esc/Backspace = back"
-	DrawString(screen, w-len(message)-1, h-1, opts.Style, message)
+	DrawHelpHint(screen, opts.Style, "W/S = up/down, A/D = change, Enter = select, Esc/Backspace = back")
+}
+
+// The difficulties offered on the main menu. Custom ones are available in
+// NG mode too, their page being where an NG board's seed is entered back.
+var difficulties = []string{"beginner", "intermediate", "advanced", "expert", "insane", "custom"}
+var difficultiesNG = []string{"beginner", "intermediate", "advanced", "expert", "insane", "custom"}
+
+var volPercentages = []int{0, 10, 20, 30, 40, 50, 60, 70, 80, 90, 100}
+
+func backgroundNames() []string {
+	return append([]string{"none"}, assets.ListBackgrounds()...)
 }
 
 func moveSelection(selected *int, delta, menuCount int) {
@@ -205,33 +403,65 @@ func adjustOptions(selected, delta int, bgs []string, volPercentages []int, opts
 		opts.NextBackground(delta, bgs)
 	case 3:
 		opts.NextVolume(delta, volPercentages)
+	case 4:
+		opts.NextNGBand(delta)
+	case 5:
+		opts.TogglePractice()
+	case 6:
+		opts.NextBotPace(delta)
 	}
 }
 
-func RunMenu(screen tcell.Screen, opts *GameOptions) (GameState, *GameOptions, DifficultyConfig, bool) {
+func RunMenu(screen tcell.Screen, opts *GameOptions) (GameState, *GameOptions, minesweeper.DifficultyConfig, int64, bool) {
 	page := PageMain
 	titleItems := assets.RandomTitle()
-	bgs := append([]string{"none"}, assets.ListBackgrounds()...)
-	volPercentages := []int{0, 10, 20, 30, 40, 50, 60, 70, 80, 90, 100}
+	bgs := backgroundNames()
 	selected := 0
-	difficulties := []string{"beginner", "intermediate", "advanced", "expert", "insane", "custom"}
-	difficultiesNG := []string{"beginner", "intermediate", "advanced", "expert", "insane"}
-	diffIndex := 0
-	diffNGIndex := 0
+	mainItems := mainMenuItems(HasSavedGame(), HasLastReplay())
 	playingNG := false
-	customCfg := DifficultyConfig{Rows: 9, Cols: 9, BombCount: 10}
-	rowsOptions := make([]int, MAX_ROWS)
-	for i := range MAX_ROWS {
+	// Whether the custom difficulty page starts a game for the bot
+	watchingBot := false
+	rowsOptions := make([]int, minesweeper.MAX_ROWS)
+	for i := range minesweeper.MAX_ROWS {
 		rowsOptions[i] = i + 1
 	}
-	colsOptions := make([]int, MAX_COLS)
-	for i := range MAX_COLS {
+	colsOptions := make([]int, minesweeper.MAX_COLS)
+	for i := range minesweeper.MAX_COLS {
 		colsOptions[i] = i + 1
 	}
-	rowsIndex := 8
-	colsIndex := 8
+	rowsIndex := opts.CustomDifficulty.Rows - 1
+	colsIndex := opts.CustomDifficulty.Cols - 1
+	// Negative custom seed means a random seed is picked on start
+	customSeed := int64(-1)
 	inputBuffer := ""
 	errorMsg := ""
+	// Statistics page, loaded when opened
+	stats := NewStats()
+	statsCategories := stats.CategoryNames()
+	statsIndex := 0
+	confirmReset := false
+	// High scores page, loaded when opened
+	highScores := NewHighScores()
+	highScoreCategories := highScores.CategoryNames()
+	highScoreIndex := 0
+	nextHighScoreCategory := func(delta int) {
+		highScoreIndex = (highScoreIndex + delta + len(highScoreCategories)) % len(highScoreCategories)
+	}
+	nextStatsCategory := func(delta int) {
+		statsIndex = (statsIndex + delta + len(statsCategories)) % len(statsCategories)
+		confirmReset = false
+		errorMsg = ""
+	}
+
+	// Options get saved to the config file as soon as they change
+	savedOpts := *opts
+	saveChangedOptions := func() {
+		if *opts != savedOpts {
+			savedOpts = *opts
+			saveOptions(screen, opts)
+		}
+	}
+	defer saveChangedOptions()
 
 	var menuCount int
 
@@ -243,7 +473,7 @@ func RunMenu(screen tcell.Screen, opts *GameOptions) (GameState, *GameOptions, D
 		DrawBackground(screen, bgs[opts.bgIndex], false)
 		switch page {
 		case PageMain:
-			menuCount = drawMainMenu(screen, titleItems, selected, difficulties[diffIndex], difficultiesNG[diffNGIndex], opts)
+			menuCount = drawMainMenu(screen, titleItems, selected, mainItems, difficulties[opts.DifficultyIndex], difficultiesNG[opts.DifficultyNGIndex], opts)
 		case PageOptions:
 			menuCount = drawOptionsMenu(screen, titleItems, selected, opts)
 		case PageCredits:
@@ -251,7 +481,17 @@ func RunMenu(screen tcell.Screen, opts *GameOptions) (GameState, *GameOptions, D
 		case PageQuitConfirm:
 			drawQuitConfirm(screen, opts)
 		case PageCustomInput:
-			menuCount = drawCustomInput(screen, titleItems, selected, customCfg, inputBuffer, errorMsg, opts)
+			menuCount = drawCustomInput(screen, titleItems, selected, opts.CustomDifficulty, customSeed, inputBuffer, errorMsg, opts)
+		case PageStatistics:
+			category := statsCategories[statsIndex]
+			message := errorMsg
+			if confirmReset {
+				message = fmt.Sprintf("Press Enter again to reset %s", category)
+			}
+			menuCount = drawStatistics(screen, selected, category, stats.Category(category), message, opts)
+		case PageHighScores:
+			category := highScoreCategories[highScoreIndex]
+			menuCount = drawHighScores(screen, selected, category, highScores.Tables[category], errorMsg, opts)
 		}
 		drawHelpHint(screen, opts)
 		screen.Show()
@@ -272,87 +512,144 @@ func RunMenu(screen tcell.Screen, opts *GameOptions) (GameState, *GameOptions, D
 					}
 				case tcell.KeyUp:
 					switch page {
-					case PageMain, PageOptions, PageCustomInput:
+					case PageMain, PageOptions, PageCustomInput, PageStatistics, PageHighScores:
 						moveSelection(&selected, -1, menuCount)
+						confirmReset = false
 					}
 				case tcell.KeyDown:
 					switch page {
-					case PageMain, PageOptions, PageCustomInput:
+					case PageMain, PageOptions, PageCustomInput, PageStatistics, PageHighScores:
 						moveSelection(&selected, 1, menuCount)
+						confirmReset = false
 					}
 				case tcell.KeyLeft:
 					switch page {
 					case PageMain:
-						switch selected {
-						case 0:
-							diffIndex = (diffIndex - 1 + len(difficulties)) % len(difficulties)
-						case 1:
-							diffNGIndex = (diffNGIndex - 1 + len(difficultiesNG)) % len(difficultiesNG)
+						switch mainItems[selected] {
+						case MainItemPlay:
+							opts.DifficultyIndex = (opts.DifficultyIndex - 1 + len(difficulties)) % len(difficulties)
+						case MainItemPlayNG:
+							opts.DifficultyNGIndex = (opts.DifficultyNGIndex - 1 + len(difficultiesNG)) % len(difficultiesNG)
+						case MainItemWatchBot:
+							opts.NextBotStrategy(-1)
 						}
 					case PageOptions:
 						adjustOptions(selected, -1, bgs, volPercentages, opts)
+					case PageStatistics:
+						if selected == 0 {
+							nextStatsCategory(-1)
+						}
+					case PageHighScores:
+						if selected == 0 {
+							nextHighScoreCategory(-1)
+						}
 					case PageCustomInput:
 						switch selected {
 						case 0:
 							rowsIndex = (rowsIndex - 1 + len(rowsOptions)) % len(rowsOptions)
-							customCfg.Rows = rowsOptions[rowsIndex]
+							opts.CustomDifficulty.Rows = rowsOptions[rowsIndex]
 						case 1:
 							colsIndex = (colsIndex - 1 + len(colsOptions)) % len(colsOptions)
-							customCfg.Cols = colsOptions[colsIndex]
+							opts.CustomDifficulty.Cols = colsOptions[colsIndex]
 						}
 					}
 				case tcell.KeyRight:
 					switch page {
 					case PageMain:
-						switch selected {
-						case 0:
-							diffIndex = (diffIndex + 1) % len(difficulties)
-						case 1:
-							diffNGIndex = (diffNGIndex + 1) % len(difficultiesNG)
+						switch mainItems[selected] {
+						case MainItemPlay:
+							opts.DifficultyIndex = (opts.DifficultyIndex + 1) % len(difficulties)
+						case MainItemPlayNG:
+							opts.DifficultyNGIndex = (opts.DifficultyNGIndex + 1) % len(difficultiesNG)
+						case MainItemWatchBot:
+							opts.NextBotStrategy(1)
 						}
 					case PageOptions:
 						adjustOptions(selected, 1, bgs, volPercentages, opts)
+					case PageStatistics:
+						if selected == 0 {
+							nextStatsCategory(1)
+						}
+					case PageHighScores:
+						if selected == 0 {
+							nextHighScoreCategory(1)
+						}
 					case PageCustomInput:
 						switch selected {
 						case 0:
 							rowsIndex = (rowsIndex + 1) % len(rowsOptions)
-							customCfg.Rows = rowsOptions[rowsIndex]
+							opts.CustomDifficulty.Rows = rowsOptions[rowsIndex]
 						case 1:
 							colsIndex = (colsIndex + 1) % len(colsOptions)
-							customCfg.Cols = colsOptions[colsIndex]
+							opts.CustomDifficulty.Cols = colsOptions[colsIndex]
 						}
 					}
 				case tcell.KeyEnter:
 					switch page {
 					case PageMain:
-						switch selected {
-						// Play
-						case 0:
-							playingNG = false
-							if difficulties[diffIndex] == "custom" {
+						switch mainItems[selected] {
+						case MainItemContinue:
+							return StateContinue, opts, minesweeper.DifficultyConfig{}, 0, false
+						case MainItemPlay:
+							playingNG, watchingBot = false, false
+							if difficulties[opts.DifficultyIndex] == "custom" {
 								page = PageCustomInput
+								selected = 0
 							} else {
-								opts.Difficulty = DifficultyMap[difficulties[diffIndex]]
-								return StatePlaying, opts, DifficultyMap[difficulties[diffIndex]], playingNG
+								opts.Difficulty = minesweeper.DifficultyMap[difficulties[opts.DifficultyIndex]]
+								return StatePlaying, opts, minesweeper.DifficultyMap[difficulties[opts.DifficultyIndex]], minesweeper.NewSeed(), playingNG
 							}
-						// Play NG
-						case 1:
-							playingNG = true
-							if difficultiesNG[diffNGIndex] == "custom" {
+						case MainItemPlayNG:
+							playingNG, watchingBot = true, false
+							if difficultiesNG[opts.DifficultyNGIndex] == "custom" {
+								page = PageCustomInput
+								selected = 0
+							} else {
+								opts.Difficulty = minesweeper.DifficultyMap[difficultiesNG[opts.DifficultyNGIndex]]
+								return StatePlaying, opts, minesweeper.DifficultyMap[difficultiesNG[opts.DifficultyNGIndex]], minesweeper.NewSeed(), playingNG
+							}
+						case MainItemWatchBot:
+							// The bot plays the difficulty picked for "Play"
+							playingNG, watchingBot = false, true
+							if difficulties[opts.DifficultyIndex] == "custom" {
 								page = PageCustomInput
+								selected = 0
 							} else {
-								opts.Difficulty = DifficultyMap[difficultiesNG[diffNGIndex]]
-								return StatePlaying, opts, DifficultyMap[difficultiesNG[diffNGIndex]], playingNG
+								return StateWatchBot, opts, minesweeper.DifficultyMap[difficulties[opts.DifficultyIndex]], minesweeper.NewSeed(), false
 							}
-						// Options
-						case 2:
+						case MainItemReplay:
+							return StateReplay, opts, minesweeper.DifficultyConfig{}, 0, false
+						case MainItemOptions:
 							page = PageOptions
 							selected = 0
-						// Credits
-						case 3:
+						case MainItemStatistics:
+							page = PageStatistics
+							selected = 0
+							confirmReset = false
+							errorMsg = ""
+							st, err := LoadStats()
+							if err != nil {
+								st = NewStats()
+								errorMsg = "Error: " + err.Error()
+							}
+							stats = st
+							statsCategories = stats.CategoryNames()
+							statsIndex = 0
+						case MainItemHighScores:
+							page = PageHighScores
+							selected = 0
+							errorMsg = ""
+							hs, err := LoadHighScores()
+							if err != nil {
+								hs = NewHighScores()
+								errorMsg = "Error: " + err.Error()
+							}
+							highScores = hs
+							highScoreCategories = highScores.CategoryNames()
+							highScoreIndex = 0
+						case MainItemCredits:
 							page = PageCredits
-						// Quit
-						case menuCount - 1:
+						case MainItemQuit:
 							page = PageQuitConfirm
 						}
 					case PageOptions:
@@ -360,16 +657,49 @@ func RunMenu(screen tcell.Screen, opts *GameOptions) (GameState, *GameOptions, D
 							page = PageMain
 							selected = 0
 						}
+					case PageStatistics:
+						switch selected {
+						// Reset, asking for confirmation first
+						case 1:
+							if !confirmReset {
+								confirmReset = true
+								break
+							}
+							confirmReset = false
+							stats.Reset(statsCategories[statsIndex])
+							if err := SaveStats(stats); err != nil {
+								errorMsg = "Error: " + err.Error()
+							} else {
+								errorMsg = ""
+							}
+						// Back
+						case menuCount - 1:
+							page = PageMain
+							selected = 0
+							errorMsg = ""
+						}
+					case PageHighScores:
+						if selected == menuCount-1 {
+							page = PageMain
+							selected = 0
+							errorMsg = ""
+						}
 					case PageCustomInput:
 						switch selected {
 						// Start
-						case 3:
-							_, err := GenerateBoardWithStartCell(customCfg)
+						case 4:
+							seed := customSeed
+							if seed < 0 {
+								seed = minesweeper.NewSeed()
+							}
+							_, err := minesweeper.GenerateBoardWithStartCell(opts.CustomDifficulty, seed)
 							if err != nil {
 								errorMsg = err.Error()
+							} else if watchingBot {
+								return StateWatchBot, opts, opts.CustomDifficulty, seed, false
 							} else {
-								opts.Difficulty = customCfg
-								return StatePlaying, opts, customCfg, playingNG
+								opts.Difficulty = opts.CustomDifficulty
+								return StatePlaying, opts, opts.CustomDifficulty, seed, playingNG
 							}
 						// Back
 						case menuCount - 1:
@@ -379,10 +709,13 @@ func RunMenu(screen tcell.Screen, opts *GameOptions) (GameState, *GameOptions, D
 							errorMsg = ""
 						default:
 							if inputBuffer != "" {
-								val, err := strconv.Atoi(inputBuffer)
+								val, err := strconv.ParseInt(inputBuffer, 10, 64)
 								if err == nil {
-									if selected == 2 {
-										customCfg.BombCount = val
+									switch selected {
+									case 2:
+										opts.CustomDifficulty.BombCount = int(val)
+									case 3:
+										customSeed = val
 									}
 								} else {
 									errorMsg = err.Error()
@@ -400,69 +733,93 @@ func RunMenu(screen tcell.Screen, opts *GameOptions) (GameState, *GameOptions, D
 					}
 					if len(inputBuffer) > 0 {
 						inputBuffer = inputBuffer[:len(inputBuffer)-1]
+					} else if page == PageCustomInput && selected == 3 {
+						// Clearing an empty seed input goes back to a random seed
+						customSeed = -1
 					}
 				case tcell.KeyRune:
 					r := ev.Rune()
-					if page == PageCustomInput && selected == 2 && unicode.IsDigit(r) {
+					if page == PageCustomInput && (selected == 2 || selected == 3) && unicode.IsDigit(r) {
 						inputBuffer += string(r)
 					} else {
 						switch r {
 						case 'w':
 							switch page {
-							case PageMain, PageOptions, PageCustomInput:
+							case PageMain, PageOptions, PageCustomInput, PageStatistics, PageHighScores:
 								moveSelection(&selected, -1, menuCount)
+								confirmReset = false
 							}
 						case 's':
 							switch page {
-							case PageMain, PageOptions, PageCustomInput:
+							case PageMain, PageOptions, PageCustomInput, PageStatistics, PageHighScores:
 								moveSelection(&selected, 1, menuCount)
+								confirmReset = false
 							}
 						case 'a':
 							switch page {
 							case PageMain:
-								switch selected {
-								case 0:
-									diffIndex = (diffIndex - 1 + len(difficulties)) % len(difficulties)
-								case 1:
-									diffNGIndex = (diffNGIndex - 1 + len(difficultiesNG)) % len(difficultiesNG)
+								switch mainItems[selected] {
+								case MainItemPlay:
+									opts.DifficultyIndex = (opts.DifficultyIndex - 1 + len(difficulties)) % len(difficulties)
+								case MainItemPlayNG:
+									opts.DifficultyNGIndex = (opts.DifficultyNGIndex - 1 + len(difficultiesNG)) % len(difficultiesNG)
+								case MainItemWatchBot:
+									opts.NextBotStrategy(-1)
 								}
 							case PageOptions:
 								adjustOptions(selected, -1, bgs, volPercentages, opts)
+							case PageStatistics:
+								if selected == 0 {
+									nextStatsCategory(-1)
+								}
+							case PageHighScores:
+								if selected == 0 {
+									nextHighScoreCategory(-1)
+								}
 							case PageCustomInput:
 								switch selected {
 								case 0:
 									rowsIndex = (rowsIndex - 1 + len(rowsOptions)) % len(rowsOptions)
-									customCfg.Rows = rowsOptions[rowsIndex]
+									opts.CustomDifficulty.Rows = rowsOptions[rowsIndex]
 								case 1:
 									colsIndex = (colsIndex - 1 + len(colsOptions)) % len(colsOptions)
-									customCfg.Cols = colsOptions[colsIndex]
+									opts.CustomDifficulty.Cols = colsOptions[colsIndex]
 								}
 							}
 						case 'd':
 							switch page {
 							case PageMain:
-								switch selected {
-								case 0:
-									diffIndex = (diffIndex + 1) % len(difficulties)
-								case 1:
-									diffNGIndex = (diffNGIndex + 1) % len(difficultiesNG)
+								switch mainItems[selected] {
+								case MainItemPlay:
+									opts.DifficultyIndex = (opts.DifficultyIndex + 1) % len(difficulties)
+								case MainItemPlayNG:
+									opts.DifficultyNGIndex = (opts.DifficultyNGIndex + 1) % len(difficultiesNG)
+								case MainItemWatchBot:
+									opts.NextBotStrategy(1)
 								}
 							case PageOptions:
 								adjustOptions(selected, 1, bgs, volPercentages, opts)
-
+							case PageStatistics:
+								if selected == 0 {
+									nextStatsCategory(1)
+								}
+							case PageHighScores:
+								if selected == 0 {
+									nextHighScoreCategory(1)
+								}
 							case PageCustomInput:
 								switch selected {
 								case 0:
 									rowsIndex = (rowsIndex + 1) % len(rowsOptions)
-									customCfg.Rows = rowsOptions[rowsIndex]
+									opts.CustomDifficulty.Rows = rowsOptions[rowsIndex]
 								case 1:
 									colsIndex = (colsIndex + 1) % len(colsOptions)
-									customCfg.Cols = colsOptions[colsIndex]
+									opts.CustomDifficulty.Cols = colsOptions[colsIndex]
 								}
 							}
 						case 'y':
 							if page == PageQuitConfirm {
-								return StateQuit, opts, DifficultyConfig{}, false
+								return StateQuit, opts, minesweeper.DifficultyConfig{}, 0, false
 							}
 						case 'n':
 							if page == PageQuitConfirm {
@@ -473,6 +830,7 @@ func RunMenu(screen tcell.Screen, opts *GameOptions) (GameState, *GameOptions, D
 					}
 				}
 			}
+			saveChangedOptions()
 		default:
 		}
 	}
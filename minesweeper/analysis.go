@@ -0,0 +1,115 @@
+package minesweeper
+
+import "math"
+
+// Analysis describes how hard a board is to clear
+type Analysis struct {
+	// ThreeBV is the minimum number of clicks clearing the board without
+	// flags: one per opening, plus one per number not bordering an opening
+	ThreeBV int
+	// Openings are the connected regions of clear cells
+	Openings int
+	// Islands are the connected regions of numbers not bordering an opening
+	Islands int
+	// Solvable tells whether DeterministicSolve clears the board
+	Solvable bool
+	Stats    SolveStats
+	// Difficulty rates the board from 0 (trivial) to 100
+	Difficulty float64
+}
+
+// DifficultyBand is a range of Analysis.Difficulty scores
+type DifficultyBand struct {
+	Min float64
+	Max float64
+}
+
+// AnyDifficulty accepts every board
+var AnyDifficulty = DifficultyBand{Min: 0, Max: 100}
+
+func (b DifficultyBand) Contains(difficulty float64) bool {
+	return b.Min <= difficulty && difficulty <= b.Max
+}
+
+// Analyze computes the 3BV, openings and islands of the board, and the
+// solver statistics of DeterministicSolve on it
+func (m *Minesweeper) Analyze(maxComponentSize int) Analysis {
+	var a Analysis
+
+	// --- Openings: flood the clear cells ---
+	seen := make(map[[2]int]struct{})
+	flood := func(start [2]int, include func(pos [2]int) bool) {
+		queue := [][2]int{start}
+		seen[start] = struct{}{}
+		for len(queue) > 0 {
+			pos := queue[0]
+			queue = queue[1:]
+			for _, neighbor := range m.getNeighborsOf(pos[0], pos[1]) {
+				if _, ok := seen[neighbor]; !ok && include(neighbor) {
+					seen[neighbor] = struct{}{}
+					queue = append(queue, neighbor)
+				}
+			}
+		}
+	}
+	isClear := func(pos [2]int) bool {
+		return m.Grid[pos[0]][pos[1]].Value == CLEAR
+	}
+	for row := range m.Rows {
+		for col := range m.Cols {
+			pos := [2]int{row, col}
+			if _, ok := seen[pos]; !ok && isClear(pos) {
+				a.Openings++
+				flood(pos, isClear)
+			}
+		}
+	}
+
+	// --- Islands: numbers left out of every opening ---
+	bordersOpening := func(pos [2]int) bool {
+		for _, neighbor := range m.getNeighborsOf(pos[0], pos[1]) {
+			if isClear(neighbor) {
+				return true
+			}
+		}
+		return false
+	}
+	isIslandCell := func(pos [2]int) bool {
+		return m.Grid[pos[0]][pos[1]].Value > 0 && !bordersOpening(pos)
+	}
+	isolatedNumbers := 0
+	for row := range m.Rows {
+		for col := range m.Cols {
+			pos := [2]int{row, col}
+			if !isIslandCell(pos) {
+				continue
+			}
+			isolatedNumbers++
+			if _, ok := seen[pos]; !ok {
+				a.Islands++
+				flood(pos, isIslandCell)
+			}
+		}
+	}
+	a.ThreeBV = a.Openings + isolatedNumbers
+
+	// --- Solver statistics ---
+	a.Solvable, _, _ = m.deterministicSolve(maxComponentSize, &a.Stats)
+	a.Difficulty = difficultyScore(a, m.Rows*m.Cols-m.BombCount)
+
+	return a
+}
+
+// difficultyScore mixes the 3BV density (more clicks per safe cell means
+// fewer free openings) with the thinking the solver needed: every round
+// past the simple rules weighs in, the costlier rules more
+func difficultyScore(a Analysis, safeCells int) float64 {
+	density := 0.
+	if safeCells > 0 {
+		density = float64(a.ThreeBV) / float64(safeCells)
+	}
+	thinking := float64(a.Stats.SubsetRounds + a.Stats.EliminationRounds + 2*a.Stats.SearchRounds + 3*a.Stats.GlobalRounds)
+
+	score := 40*density + 60*(1-math.Exp(-thinking/10))
+	return math.Round(max(0, min(100, score))*10) / 10
+}
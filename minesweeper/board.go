@@ -0,0 +1,233 @@
+package minesweeper
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// BOARD_VERSION is bumped whenever the JSON board layout changes
+const BOARD_VERSION = 1
+
+var (
+	ErrInvalidBoard            = errors.New("board is invalid")
+	ErrUnsupportedBoardVersion = errors.New("board version is not supported")
+)
+
+// Board is a shareable snapshot of a board: its layout and which cells
+// are revealed and flagged. Unlike a saved game it holds no clock nor
+// moves, so it also fits hand-designed puzzles and test fixtures.
+//
+// As JSON (see board.schema.json) positions are [row, col] pairs counted
+// from 0, a startCell of [-1, -1] meaning none:
+//
+//	{
+//	  "version": 1,
+//	  "rows": 9,
+//	  "cols": 9,
+//	  "seed": 42,
+//	  "bombPositions": [[0, 5], [2, 1]],
+//	  "startCell": [4, 8],
+//	  "revealed": [[4, 8]],
+//	  "flagged": [[0, 5]]
+//	}
+//
+// As text (see Board.WriteText) it's one line per row and one character
+// per cell:
+//
+//	.    hidden cell
+//	*    hidden bomb
+//	0-8  revealed cell, the digit being its number
+//	F    flagged bomb
+//	f    flag on a cell without bomb
+//	X    revealed bomb, the game being lost
+//
+// Lines "seed: 42" and "start: 4,8" before the grid give the seed and the
+// start cell. Spaces between cells, blank lines and lines starting with
+// '#' are ignored.
+type Board struct {
+	Version       int      `json:"version"`
+	Rows          int      `json:"rows"`
+	Cols          int      `json:"cols"`
+	Seed          int64    `json:"seed"`
+	BombPositions [][2]int `json:"bombPositions"`
+	StartCell     [2]int   `json:"startCell"`
+	Revealed      [][2]int `json:"revealed"`
+	Flagged       [][2]int `json:"flagged"`
+}
+
+// Board captures the board as it is now
+func (m *Minesweeper) Board() Board {
+	b := Board{
+		Version:       BOARD_VERSION,
+		Rows:          m.Rows,
+		Cols:          m.Cols,
+		Seed:          m.Seed,
+		BombPositions: m.BombPositions,
+		StartCell:     m.StartCellPosition,
+	}
+	b.Revealed, b.Flagged = m.revealedAndFlagged()
+	return b
+}
+
+// NewBoard rebuilds the board with its cells revealed and flagged, the
+// game being over when they end it. The clock isn't started.
+func (b *Board) NewBoard() (*Minesweeper, error) {
+	m, err := NewBoard(b.Rows, b.Cols, b.BombPositions, b.StartCell)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidBoard, err)
+	}
+	m.Seed = b.Seed
+	if err := m.restoreCells(b.Revealed, b.Flagged); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidBoard, err)
+	}
+	return m, nil
+}
+
+func (b *Board) Save(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(b)
+}
+
+// LoadBoard reads a board written by Board.Save and checks that it can
+// be played
+func LoadBoard(r io.Reader) (*Board, error) {
+	var b Board
+	if err := json.NewDecoder(r).Decode(&b); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidBoard, err)
+	}
+	if b.Version != BOARD_VERSION {
+		return nil, fmt.Errorf("%w: got version %d, expected %d", ErrUnsupportedBoardVersion, b.Version, BOARD_VERSION)
+	}
+	if _, err := b.NewBoard(); err != nil {
+		return nil, err
+	}
+	return &b, nil
+}
+
+// WriteText writes the board in the text format ParseBoard reads
+func (b *Board) WriteText(w io.Writer) error {
+	m, err := b.NewBoard()
+	if err != nil {
+		return err
+	}
+
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "seed: %d\n", m.Seed)
+	if m.StartCellPosition != [2]int{-1, -1} {
+		fmt.Fprintf(bw, "start: %d,%d\n", m.StartCellPosition[0], m.StartCellPosition[1])
+	}
+	for _, row := range m.Grid {
+		for _, cell := range row {
+			bw.WriteByte(cellChar(cell))
+		}
+		bw.WriteByte('\n')
+	}
+	return bw.Flush()
+}
+
+func cellChar(cell Cell) byte {
+	isBomb := cell.Value == BOMB
+	switch {
+	case cell.Revealed && isBomb:
+		return 'X'
+	case cell.Revealed:
+		return byte('0' + cell.Value)
+	case cell.Flagged && isBomb:
+		return 'F'
+	case cell.Flagged:
+		return 'f'
+	case isBomb:
+		return '*'
+	}
+	return '.'
+}
+
+// ParseBoard reads a board in the text format described on Board. Digits
+// must match the bombs around them.
+func ParseBoard(r io.Reader) (*Board, error) {
+	b := Board{Version: BOARD_VERSION, StartCell: [2]int{-1, -1}}
+	// Revealed numbers, checked once every bomb is known
+	numbers := make(map[[2]int]int)
+
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		// --- Header lines ---
+		if key, value, ok := strings.Cut(text, ":"); ok {
+			if b.Rows > 0 {
+				return nil, fmt.Errorf("%w: line %d: %q comes after the grid", ErrInvalidBoard, line, key)
+			}
+			value = strings.TrimSpace(value)
+			var err error
+			switch strings.TrimSpace(key) {
+			case "seed":
+				b.Seed, err = strconv.ParseInt(value, 10, 64)
+			case "start":
+				_, err = fmt.Sscanf(value, "%d,%d", &b.StartCell[0], &b.StartCell[1])
+			default:
+				err = fmt.Errorf("unknown key %q", key)
+			}
+			if err != nil {
+				return nil, fmt.Errorf("%w: line %d: %v", ErrInvalidBoard, line, err)
+			}
+			continue
+		}
+
+		// --- Grid lines ---
+		row := b.Rows
+		col := 0
+		for _, c := range text {
+			pos := [2]int{row, col}
+			switch {
+			case c == ' ' || c == '\t':
+				continue
+			case '0' <= c && c <= '8':
+				b.Revealed = append(b.Revealed, pos)
+				numbers[pos] = int(c - '0')
+			case c == '.':
+			case c == '*':
+				b.BombPositions = append(b.BombPositions, pos)
+			case c == 'F':
+				b.BombPositions = append(b.BombPositions, pos)
+				b.Flagged = append(b.Flagged, pos)
+			case c == 'f':
+				b.Flagged = append(b.Flagged, pos)
+			case c == 'X':
+				b.BombPositions = append(b.BombPositions, pos)
+				b.Revealed = append(b.Revealed, pos)
+			default:
+				return nil, fmt.Errorf("%w: line %d: unexpected %q", ErrInvalidBoard, line, c)
+			}
+			col++
+		}
+		if row > 0 && col != b.Cols {
+			return nil, fmt.Errorf("%w: line %d has %d cells, expected %d", ErrInvalidBoard, line, col, b.Cols)
+		}
+		b.Cols = col
+		b.Rows++
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	m, err := b.NewBoard()
+	if err != nil {
+		return nil, err
+	}
+	for pos, number := range numbers {
+		if value := m.Grid[pos[0]][pos[1]].Value; value != number {
+			return nil, fmt.Errorf("%w: cell %d,%d shows %d but touches %d bombs", ErrInvalidBoard, pos[0], pos[1], number, value)
+		}
+	}
+	return &b, nil
+}
@@ -0,0 +1,249 @@
+package minesweeper
+
+import (
+	"fmt"
+	"math/rand"
+	"slices"
+)
+
+// Move is a move picked by a bot strategy, Guess telling that it wasn't
+// proven safe
+type Move struct {
+	Kind  ActionKind
+	Row   int
+	Col   int
+	Guess bool
+}
+
+// Strategy picks the moves of a bot from what a player can see. It may
+// keep state between moves, so every game needs a strategy of its own.
+type Strategy interface {
+	Name() string
+	// NextMove returns the move to play on the position, false when the
+	// strategy has none
+	NextMove(p Position) (Move, bool)
+}
+
+// --- Strategies ---
+
+// SolverStrategy only plays the moves the solver proves: it flags the
+// forced bombs, then reveals the forced safe cells, chording a number
+// when every bomb around it is flagged
+type SolverStrategy struct {
+	MaxComponentSize int
+	// Moves proven on an earlier position, checked again before playing
+	pending []Move
+}
+
+func (st *SolverStrategy) Name() string {
+	return "solver"
+}
+
+func (st *SolverStrategy) NextMove(p Position) (Move, bool) {
+	for range 2 {
+		for len(st.pending) > 0 {
+			move := st.pending[0]
+			st.pending = st.pending[1:]
+			// Cells revealed by an earlier move are skipped
+			if p.Cells[move.Row][move.Col] != UNKNOWN {
+				continue
+			}
+			if move.Kind == ActionReveal {
+				if pos, ok := chordFor(p, [2]int{move.Row, move.Col}); ok {
+					return Move{Kind: ActionChord, Row: pos[0], Col: pos[1]}, true
+				}
+			}
+			return move, true
+		}
+
+		// --- Prove the next batch of moves ---
+		s := newPositionSolver(p)
+		if !s.solve(st.MaxComponentSize) {
+			return Move{}, false
+		}
+		mines := make([][2]int, 0, len(s.mines))
+		for pos := range s.mines {
+			if p.Cells[pos[0]][pos[1]] == UNKNOWN {
+				mines = append(mines, pos)
+			}
+		}
+		safe := make([][2]int, 0, len(s.safe))
+		for pos := range s.safe {
+			safe = append(safe, pos)
+		}
+		slices.SortFunc(mines, compareReadingOrder)
+		slices.SortFunc(safe, compareReadingOrder)
+		for _, pos := range mines {
+			st.pending = append(st.pending, Move{Kind: ActionFlag, Row: pos[0], Col: pos[1]})
+		}
+		for _, pos := range safe {
+			st.pending = append(st.pending, Move{Kind: ActionReveal, Row: pos[0], Col: pos[1]})
+		}
+	}
+	return Move{}, false
+}
+
+// chordFor finds a number next to a safe cell whose bombs are all
+// flagged and that has more than one cell to open, so chording it
+// opens the safe cell along with others
+func chordFor(p Position, safe [2]int) ([2]int, bool) {
+	for _, number := range p.getNeighborsOf(safe[0], safe[1]) {
+		value := p.Cells[number[0]][number[1]]
+		if value <= 0 {
+			continue
+		}
+		flagged, unknown := 0, 0
+		for _, neighbor := range p.getNeighborsOf(number[0], number[1]) {
+			switch p.Cells[neighbor[0]][neighbor[1]] {
+			case FLAGGED:
+				flagged++
+			case UNKNOWN:
+				unknown++
+			}
+		}
+		if flagged == value && unknown > 1 {
+			return number, true
+		}
+	}
+	return [2]int{}, false
+}
+
+// ProbabilityStrategy reveals the first safe cell, or the cell least
+// likely to hold a bomb, like Hint does
+type ProbabilityStrategy struct {
+	MaxComponentSize int
+}
+
+func (st *ProbabilityStrategy) Name() string {
+	return "probability"
+}
+
+func (st *ProbabilityStrategy) NextMove(p Position) (Move, bool) {
+	hint, ok := pickCell(p, st.MaxComponentSize)
+	if !ok {
+		return Move{}, false
+	}
+	return Move{Kind: ActionReveal, Row: hint.Position[0], Col: hint.Position[1], Guess: !hint.Safe}, true
+}
+
+// RandomStrategy reveals any unknown cell, as a baseline for the others
+type RandomStrategy struct {
+	Rand *rand.Rand
+}
+
+func (st *RandomStrategy) Name() string {
+	return "random"
+}
+
+func (st *RandomStrategy) NextMove(p Position) (Move, bool) {
+	unknown := make([][2]int, 0)
+	for row := range p.Rows {
+		for col := range p.Cols {
+			if p.Cells[row][col] == UNKNOWN {
+				unknown = append(unknown, [2]int{row, col})
+			}
+		}
+	}
+	if len(unknown) == 0 {
+		return Move{}, false
+	}
+	pos := unknown[st.Rand.Intn(len(unknown))]
+	return Move{Kind: ActionReveal, Row: pos[0], Col: pos[1], Guess: true}, true
+}
+
+// chainStrategy asks each strategy in turn until one has a move
+type chainStrategy []Strategy
+
+// Chain builds a strategy falling back on the next strategy whenever
+// the ones before it have no move
+func Chain(strategies ...Strategy) Strategy {
+	return chainStrategy(strategies)
+}
+
+func (c chainStrategy) Name() string {
+	name := ""
+	for i, st := range c {
+		if i > 0 {
+			name += "+"
+		}
+		name += st.Name()
+	}
+	return name
+}
+
+func (c chainStrategy) NextMove(p Position) (Move, bool) {
+	for _, st := range c {
+		if move, ok := st.NextMove(p); ok {
+			return move, true
+		}
+	}
+	return Move{}, false
+}
+
+// StrategyNames lists the strategies NewStrategy builds, the default
+// first. They all start with the solver and only differ in how they guess.
+var StrategyNames = []string{"solver+probability", "solver+random"}
+
+// NewStrategy builds a strategy by name, seed driving its random guesses
+func NewStrategy(name string, maxComponentSize int, seed int64) (Strategy, error) {
+	solver := &SolverStrategy{MaxComponentSize: maxComponentSize}
+	switch name {
+	case "solver+probability":
+		return Chain(solver, &ProbabilityStrategy{MaxComponentSize: maxComponentSize}), nil
+	case "solver+random":
+		return Chain(solver, &RandomStrategy{Rand: NewRand(seed)}), nil
+	}
+	return nil, fmt.Errorf("unknown strategy %q", name)
+}
+
+// --- Bot ---
+
+// Bot plays a game move by move with a strategy, through Play so the
+// game records the moves like the player's
+type Bot struct {
+	m        *Minesweeper
+	strategy Strategy
+	// Moves and Guesses count the moves played so far, and how many of
+	// them weren't proven safe
+	Moves   int
+	Guesses int
+}
+
+func NewBot(m *Minesweeper, strategy Strategy) *Bot {
+	return &Bot{m: m, strategy: strategy}
+}
+
+// Step plays the next move, opening the start cell first when there's
+// one. It returns false once the game is over or the strategy is stuck.
+func (b *Bot) Step() (Move, bool) {
+	if b.m.IsGameOver {
+		return Move{}, false
+	}
+
+	var move Move
+	if b.m.RevealedCount == 0 && b.m.StartCell != nil && !b.m.StartCell.Flagged {
+		move = Move{Kind: ActionReveal, Row: b.m.StartCellPosition[0], Col: b.m.StartCellPosition[1]}
+	} else {
+		var ok bool
+		if move, ok = b.strategy.NextMove(b.m.Position()); !ok {
+			return Move{}, false
+		}
+	}
+
+	b.m.Play(move.Kind, move.Row, move.Col)
+	b.Moves++
+	if move.Guess {
+		b.Guesses++
+	}
+	return move, true
+}
+
+// Run plays until the game is over or the strategy is stuck, and reports
+// whether the game is won
+func (b *Bot) Run() bool {
+	for {
+		if _, ok := b.Step(); !ok {
+			return b.m.IsWon
+		}
+	}
+}
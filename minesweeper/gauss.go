@@ -0,0 +1,147 @@
+package minesweeper
+
+import (
+	"maps"
+	"slices"
+)
+
+// Coefficients past this bound make eliminate give up, leaving the
+// component to the search instead of risking an overflow
+const MAX_ELIMINATION_COEFFICIENT = 1 << 30
+
+// eliminate reduces the constraints of a component, seen as a linear
+// system over its cells, to reduced row echelon form. Cells being 0 or 1,
+// a reduced row whose right-hand side equals the smallest (or largest)
+// value its left-hand side can take forces every cell it mentions. It
+// returns the forced safe cells and bombs, and false when some row can't
+// be satisfied at all.
+func eliminate(component [][2]int, constraints map[[2]int]Constraint) (safe, mines [][2]int, consistent bool) {
+	N := len(component)
+	indexOf := make(map[[2]int]int, N)
+	for i, u := range component {
+		indexOf[u] = i
+	}
+
+	// --- Build the augmented matrix, one row per constraint ---
+	// Rows follow the reading order of the constraints, for the pivots to
+	// be the same on every run
+	matrix := make([][]int64, 0)
+	for _, key := range slices.SortedFunc(maps.Keys(constraints), compareReadingOrder) {
+		constraint := constraints[key]
+		if len(constraint.UnknownNeighbors) == 0 {
+			continue
+		}
+		if _, ok := indexOf[constraint.UnknownNeighbors[0]]; !ok {
+			continue
+		}
+		row := make([]int64, N+1)
+		for _, u := range constraint.UnknownNeighbors {
+			row[indexOf[u]] = 1
+		}
+		row[N] = int64(constraint.RemainingValue)
+		matrix = append(matrix, row)
+	}
+
+	// --- Fraction-free elimination, rows kept small by their gcd ---
+	rank := 0
+	for col := 0; col < N && rank < len(matrix); col++ {
+		pivot := -1
+		for r := rank; r < len(matrix); r++ {
+			if matrix[r][col] != 0 {
+				pivot = r
+				break
+			}
+		}
+		if pivot < 0 {
+			continue
+		}
+		matrix[rank], matrix[pivot] = matrix[pivot], matrix[rank]
+
+		p := matrix[rank][col]
+		for r := range matrix {
+			f := matrix[r][col]
+			if r == rank || f == 0 {
+				continue
+			}
+			for c := range matrix[r] {
+				matrix[r][c] = matrix[r][c]*p - matrix[rank][c]*f
+			}
+			if !normalizeRow(matrix[r]) {
+				return nil, nil, true
+			}
+		}
+		rank++
+	}
+
+	// --- Read the forced cells off the reduced rows ---
+	forced := make([]int8, N)
+	for i := range forced {
+		forced[i] = -1
+	}
+	for _, row := range matrix {
+		var lo, hi int64
+		for _, a := range row[:N] {
+			if a < 0 {
+				lo += a
+			} else {
+				hi += a
+			}
+		}
+		rhs := row[N]
+		if rhs < lo || rhs > hi {
+			return nil, nil, false
+		}
+		if rhs != lo && rhs != hi {
+			continue
+		}
+
+		for i, a := range row[:N] {
+			if a == 0 {
+				continue
+			}
+			// At the lowest bound, positive cells are safe and negative
+			// ones bombs; the other way round at the highest
+			var val int8
+			if (a > 0) == (rhs == hi) {
+				val = 1
+			}
+			if forced[i] != -1 && forced[i] != val {
+				return nil, nil, false
+			}
+			forced[i] = val
+		}
+	}
+
+	for i, val := range forced {
+		switch val {
+		case 0:
+			safe = append(safe, component[i])
+		case 1:
+			mines = append(mines, component[i])
+		}
+	}
+	return safe, mines, true
+}
+
+// normalizeRow divides a row by the gcd of its entries. It returns false
+// when an entry grows past MAX_ELIMINATION_COEFFICIENT.
+func normalizeRow(row []int64) bool {
+	var g int64
+	for _, a := range row {
+		if a < 0 {
+			a = -a
+		}
+		if a > MAX_ELIMINATION_COEFFICIENT {
+			return false
+		}
+		for a != 0 {
+			g, a = a, g%a
+		}
+	}
+	if g > 1 {
+		for c := range row {
+			row[c] /= g
+		}
+	}
+	return true
+}
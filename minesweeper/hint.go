@@ -0,0 +1,75 @@
+package minesweeper
+
+import (
+	"cmp"
+	"slices"
+)
+
+type Hint struct {
+	Position [2]int
+	// Safe is set when the cell is guaranteed to hold no bomb
+	Safe bool
+	// Probability is the chance of the cell holding a bomb
+	Probability float64
+}
+
+// Hint runs the solver on what the player can see (revealed numbers and
+// placed flags) and points at a guaranteed safe cell, or at the cell with
+// the lowest bomb probability when no cell is safe. It returns false when
+// the game is over or the player's flags contradict the revealed numbers.
+func (m *Minesweeper) Hint(maxComponentSize int) (Hint, bool) {
+	if m.IsGameOver {
+		return Hint{}, false
+	}
+
+	// Before the first reveal, the start cell is the safe pick
+	if m.RevealedCount == 0 && m.StartCell != nil && !m.StartCell.Flagged {
+		return Hint{Position: m.StartCellPosition, Safe: true}, true
+	}
+
+	return pickCell(m.Position(), maxComponentSize)
+}
+
+// pickCell picks the first safe cell of the position in reading order,
+// or the cell least likely to hold a bomb when none is safe
+func pickCell(p Position, maxComponentSize int) (Hint, bool) {
+	// --- Deduce what can be deduced from the player's view ---
+	s := newPositionSolver(p)
+	if !s.solve(maxComponentSize) {
+		return Hint{}, false
+	}
+	if len(s.safe) > 0 {
+		safeCells := make([][2]int, 0, len(s.safe))
+		for pos := range s.safe {
+			safeCells = append(safeCells, pos)
+		}
+		return Hint{Position: slices.MinFunc(safeCells, compareReadingOrder), Safe: true}, true
+	}
+
+	// --- No safe cell left, go for the least likely bomb ---
+	probabilities, ok := s.probabilities(maxComponentSize)
+	if !ok || len(probabilities) == 0 {
+		return Hint{}, false
+	}
+
+	// Pick the lowest probability, ties broken in reading order
+	candidates := make([][2]int, 0, len(probabilities))
+	for pos := range probabilities {
+		candidates = append(candidates, pos)
+	}
+	best := slices.MinFunc(candidates, func(a, b [2]int) int {
+		if c := cmp.Compare(probabilities[a], probabilities[b]); c != 0 {
+			return c
+		}
+		return compareReadingOrder(a, b)
+	})
+
+	return Hint{Position: best, Probability: probabilities[best]}, true
+}
+
+func compareReadingOrder(a, b [2]int) int {
+	if c := cmp.Compare(a[0], b[0]); c != 0 {
+		return c
+	}
+	return cmp.Compare(a[1], b[1])
+}
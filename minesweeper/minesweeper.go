@@ -0,0 +1,620 @@
+// Package minesweeper implements the board engine and the deterministic
+// solver used by the game. It has no dependency on any UI library.
+package minesweeper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+type Cell struct {
+	Value    int
+	Revealed bool
+	Flagged  bool
+}
+
+type Minesweeper struct {
+	Rows              int
+	Cols              int
+	BombCount         int
+	Grid              [][]Cell
+	BombPositions     [][2]int
+	PositionToValue   map[[2]int]int
+	IsGameOver        bool
+	IsWon             bool
+	RevealedCount     int
+	StartCell         *Cell
+	StartCellPosition [2]int
+	Seed              int64
+	NG                bool
+	HintsUsed         int
+	// Clicks counts the player's reveals, chords and flags
+	Clicks int
+	// Actions are the moves played so far, see Play
+	Actions []Action
+	// Practice allows undoing the move that stepped on a bomb
+	Practice bool
+	// Unranked is set once a move gets undone
+	Unranked bool
+
+	// Change sets of the moves that can be undone and redone, the one
+	// being recorded by Play
+	history []ChangeSet
+	future  []ChangeSet
+	changes *ChangeSet
+
+	// Elapsed holds the play time of finished clock runs, the running
+	// one is added on top of it by ElapsedTime
+	Elapsed        time.Duration
+	clockStartedAt time.Time
+}
+
+type DifficultyConfig struct {
+	Rows      int
+	Cols      int
+	BombCount int
+}
+
+const (
+	CLEAR int = 0
+	BOMB  int = -1
+
+	MAX_ROWS int = 36
+	MAX_COLS int = 160
+
+	// Seeds are kept below MAX_SEED so they stay short enough to share
+	MAX_SEED int64 = 1_000_000_000
+)
+
+var DifficultyMap = map[string]DifficultyConfig{
+	"beginner": {
+		Rows:      9,
+		Cols:      9,
+		BombCount: 10,
+	},
+	"intermediate": {
+		Rows:      16,
+		Cols:      16,
+		BombCount: 40,
+	},
+	"advanced": {
+		Rows:      16,
+		Cols:      30,
+		BombCount: 99,
+	},
+	"expert": {
+		Rows:      24,
+		Cols:      30,
+		BombCount: 150,
+	},
+	"insane": {
+		Rows:      24,
+		Cols:      40,
+		BombCount: 210,
+	},
+}
+
+var directions = [8][2]int{
+	{-1, -1}, {-1, 0}, {-1, 1},
+	{0, -1}, {0, 1},
+	{1, -1}, {1, 0}, {1, 1},
+}
+
+func (m *Minesweeper) IsOutOfBounds(row, col int) bool {
+	return row < 0 || row >= m.Rows || col < 0 || col >= m.Cols
+}
+
+func (m *Minesweeper) getNeighborsOf(row, col int) [][2]int {
+	neighbors := make([][2]int, 0)
+	for _, direction := range directions {
+		newRow := row + direction[0]
+		newCol := col + direction[1]
+		if !m.IsOutOfBounds(newRow, newCol) {
+			neighbors = append(neighbors, [2]int{newRow, newCol})
+		}
+	}
+
+	return neighbors
+}
+
+func (m *Minesweeper) addBomb(row, col int) {
+	m.Grid[row][col].Value = BOMB
+
+	neighbors := m.getNeighborsOf(row, col)
+	for _, neighbor := range neighbors {
+		if m.Grid[neighbor[0]][neighbor[1]].Value != BOMB {
+			m.Grid[neighbor[0]][neighbor[1]].Value++
+			if _, ok := m.PositionToValue[neighbor]; !ok {
+				m.PositionToValue[neighbor] = 0
+			}
+			m.PositionToValue[neighbor]++
+		}
+	}
+}
+
+func isAdjacent(row1, col1, row2, col2 int) bool {
+	return (-1 <= row1-row2 && row1-row2 <= 1) && (-1 <= col1-col2 && col1-col2 <= 1)
+}
+
+// Validate checks that a board of this size and bomb count can be generated
+func (cfg DifficultyConfig) Validate() error {
+	if cfg.Rows <= 0 || cfg.Cols <= 0 || cfg.BombCount <= 0 {
+		return errors.New("rows, cols, and bombCount must be non-negative integer")
+	} else if cfg.Rows > MAX_ROWS {
+		return fmt.Errorf("maximum number of rows is capped at %d", MAX_ROWS)
+	} else if cfg.Cols > MAX_COLS {
+		return fmt.Errorf("maximum number of cols is capped at %d", MAX_COLS)
+	}
+
+	if cfg.BombCount >= cfg.Rows*cfg.Cols {
+		return fmt.Errorf("too many bombCount, must be in the range of [1, %d]", cfg.Rows*cfg.Cols-1)
+	}
+
+	return nil
+}
+
+// NewBoard builds a fresh board from a known bomb layout. Pass a start cell
+// position of {-1, -1} for a board without start cell.
+func NewBoard(rows, cols int, bombPositions [][2]int, startCellPosition [2]int) (*Minesweeper, error) {
+	cfg := DifficultyConfig{Rows: rows, Cols: cols, BombCount: len(bombPositions)}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	m := &Minesweeper{
+		Rows:              rows,
+		Cols:              cols,
+		BombCount:         len(bombPositions),
+		StartCellPosition: [2]int{-1, -1},
+	}
+
+	m.Grid = make([][]Cell, m.Rows)
+	for r := range m.Grid {
+		m.Grid[r] = make([]Cell, m.Cols)
+	}
+
+	m.BombPositions = make([][2]int, 0, len(bombPositions))
+	m.PositionToValue = make(map[[2]int]int)
+	for _, pos := range bombPositions {
+		if m.IsOutOfBounds(pos[0], pos[1]) {
+			return nil, fmt.Errorf("bomb position %v is out of bounds", pos)
+		}
+		if m.Grid[pos[0]][pos[1]].Value == BOMB {
+			return nil, fmt.Errorf("duplicate bomb position %v", pos)
+		}
+		m.BombPositions = append(m.BombPositions, pos)
+		m.addBomb(pos[0], pos[1])
+	}
+
+	if startCellPosition != [2]int{-1, -1} {
+		if m.IsOutOfBounds(startCellPosition[0], startCellPosition[1]) {
+			return nil, fmt.Errorf("start cell position %v is out of bounds", startCellPosition)
+		}
+		if m.Grid[startCellPosition[0]][startCellPosition[1]].Value == BOMB {
+			return nil, fmt.Errorf("start cell position %v holds a bomb", startCellPosition)
+		}
+		m.StartCellPosition = startCellPosition
+		m.StartCell = &m.Grid[startCellPosition[0]][startCellPosition[1]]
+	}
+
+	return m, nil
+}
+
+// StartClock starts the game clock if it's not already running
+func (m *Minesweeper) StartClock() {
+	if m.clockStartedAt.IsZero() {
+		m.clockStartedAt = time.Now()
+	}
+}
+
+// StopClock stops the game clock and keeps the time played so far
+func (m *Minesweeper) StopClock() {
+	if !m.clockStartedAt.IsZero() {
+		m.Elapsed += time.Since(m.clockStartedAt)
+		m.clockStartedAt = time.Time{}
+	}
+}
+
+func (m *Minesweeper) ElapsedTime() time.Duration {
+	if m.clockStartedAt.IsZero() {
+		return m.Elapsed
+	}
+	return m.Elapsed + time.Since(m.clockStartedAt)
+}
+
+// IsStarted reports whether any cell has been revealed or flagged yet
+func (m *Minesweeper) IsStarted() bool {
+	if m.RevealedCount > 0 || m.IsGameOver {
+		return true
+	}
+	for r := range m.Grid {
+		for c := range m.Grid[r] {
+			if m.Grid[r][c].Flagged {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// RemainingMines is the bomb count minus the placed flags, it goes
+// negative when the player placed too many flags
+func (m *Minesweeper) RemainingMines() int {
+	flags := 0
+	for r := range m.Grid {
+		for c := range m.Grid[r] {
+			if m.Grid[r][c].Flagged {
+				flags++
+			}
+		}
+	}
+	return m.BombCount - flags
+}
+
+func (m *Minesweeper) Reveal(row, col int, userClick bool) bool {
+	if m.IsGameOver {
+		return false
+	}
+	cell := &m.Grid[row][col]
+
+	// Don't reveal flagged cell when user clicked
+	if userClick && cell.Flagged {
+		return false
+	}
+
+	// The clock starts on the first reveal
+	if userClick {
+		m.StartClock()
+	}
+
+	// Cell with bomb is clicked/revealed
+	if cell.Value == BOMB {
+		cell.Revealed = true
+		m.recordReveal(row, col)
+		m.IsGameOver = true
+		m.StopClock()
+		return true
+	}
+
+	// Cell is already revealed
+	if cell.Revealed {
+		if userClick {
+			return m.Chord(row, col)
+		}
+		return false
+	}
+
+	// Normal cell reveal
+	cell.Revealed = true
+	m.recordReveal(row, col)
+	m.RevealedCount++
+	if m.RevealedCount == m.Rows*m.Cols-m.BombCount {
+		m.IsGameOver = true
+		m.IsWon = true
+		m.StopClock()
+		return true
+	}
+
+	// Flood-fill expansion
+	if cell.Value == CLEAR {
+		neighbors := m.getNeighborsOf(row, col)
+		for _, neighbor := range neighbors {
+			m.Reveal(neighbor[0], neighbor[1], false)
+		}
+	}
+
+	return true
+}
+
+func (m *Minesweeper) Chord(row, col int) bool {
+	cell := &m.Grid[row][col]
+	ok := false
+
+	if cell.Revealed && cell.Value > 0 {
+		unflaggedCells := make([][2]int, 0, 8)
+		flaggedCells := make([][2]int, 0, 8)
+
+		neighbors := m.getNeighborsOf(row, col)
+		for _, neighbor := range neighbors {
+			if !m.Grid[neighbor[0]][neighbor[1]].Revealed {
+				if m.Grid[neighbor[0]][neighbor[1]].Flagged {
+					flaggedCells = append(flaggedCells, neighbor)
+				} else {
+					unflaggedCells = append(unflaggedCells, neighbor)
+				}
+			}
+		}
+
+		if len(flaggedCells) == cell.Value {
+			for _, pos := range unflaggedCells {
+				r, c := pos[0], pos[1]
+				if m.Reveal(r, c, false) {
+					ok = true
+				}
+			}
+		}
+	}
+
+	return ok
+}
+
+func (m *Minesweeper) Flag(row, col int) {
+	if m.IsGameOver {
+		return
+	}
+	cell := &m.Grid[row][col]
+	if !cell.Revealed {
+		cell.Flagged = !cell.Flagged
+		m.recordToggle(row, col)
+	}
+}
+
+// NewSeed picks a fresh random seed for the board generators
+func NewSeed() int64 {
+	return rand.Int63n(MAX_SEED)
+}
+
+// NewRand creates the RNG source used by the board generators, so that
+// the same seed always yields the same sequence of draws
+func NewRand(seed int64) *rand.Rand {
+	return rand.New(rand.NewSource(seed))
+}
+
+func GenerateBoard(cfg DifficultyConfig, seed int64) (*Minesweeper, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	m := &Minesweeper{
+		Rows:              cfg.Rows,
+		Cols:              cfg.Cols,
+		BombCount:         cfg.BombCount,
+		IsGameOver:        false,
+		IsWon:             false,
+		RevealedCount:     0,
+		StartCell:         nil,
+		StartCellPosition: [2]int{-1, -1},
+		Seed:              seed,
+	}
+
+	rng := NewRand(seed)
+	m.Grid = make([][]Cell, m.Rows)
+	for r := range m.Grid {
+		m.Grid[r] = make([]Cell, m.Cols)
+	}
+
+	m.BombPositions = make([][2]int, 0)
+	m.PositionToValue = make(map[[2]int]int)
+	for len(m.BombPositions) < m.BombCount {
+		pos := rng.Intn(m.Rows * m.Cols)
+		r, c := pos/m.Cols, pos%m.Cols
+		if m.Grid[r][c].Value != BOMB {
+			m.BombPositions = append(m.BombPositions, [2]int{r, c})
+			m.addBomb(r, c)
+		}
+	}
+
+	return m, nil
+}
+
+func GenerateBoardWithStartCell(cfg DifficultyConfig, seed int64) (*Minesweeper, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	m := &Minesweeper{
+		Rows:          cfg.Rows,
+		Cols:          cfg.Cols,
+		BombCount:     cfg.BombCount,
+		IsGameOver:    false,
+		IsWon:         false,
+		RevealedCount: 0,
+		Seed:          seed,
+	}
+
+	rng := NewRand(seed)
+	m.Grid = make([][]Cell, m.Rows)
+	for r := range m.Grid {
+		m.Grid[r] = make([]Cell, m.Cols)
+	}
+
+	startCellPos := rng.Intn(m.Rows * m.Cols)
+	startCellRow, startCellCol := startCellPos/m.Cols, startCellPos%m.Cols
+	m.StartCellPosition = [2]int{startCellRow, startCellCol}
+	m.StartCell = &m.Grid[startCellRow][startCellCol]
+
+	m.BombPositions = make([][2]int, 0)
+	m.PositionToValue = make(map[[2]int]int)
+	for len(m.BombPositions) < m.BombCount {
+		pos := rng.Intn(m.Rows * m.Cols)
+		r, c := pos/m.Cols, pos%m.Cols
+		if !isAdjacent(startCellRow, startCellCol, r, c) && m.Grid[r][c].Value != BOMB {
+			m.BombPositions = append(m.BombPositions, [2]int{r, c})
+			m.addBomb(r, c)
+		}
+	}
+
+	return m, nil
+}
+
+// NGProgress reports an NG board generation worker moving to its next
+// attempt
+type NGProgress struct {
+	Worker int
+	// Attempts is the number of attempts the worker started so far
+	Attempts int
+	// Total is the number of attempts started by all workers so far
+	Total int
+}
+
+// GenerateNGBoard looks for a solvable board whose difficulty falls in
+// band, trying up to tries attempt seeds drawn from seed, each with up to
+// repairs bomb moves. Attempts run on one worker per core, and the
+// earliest accepted one wins, so the board only depends on the seed. Its
+// Seed rebuilds it through GenerateNGBoard with the same settings.
+// nil is sent when no attempt is accepted. Cancelling ctx stops the
+// workers and closes the channel without sending anything.
+func GenerateNGBoard(ctx context.Context, cfg DifficultyConfig, seed int64, tries, repairs, maxComponentSize int, band DifficultyBand) (<-chan *Minesweeper, <-chan NGProgress) {
+	return generateNGBoard(ctx, cfg, seed, tries, repairs, maxComponentSize, band, runtime.GOMAXPROCS(0))
+}
+
+// generateNGBoard is GenerateNGBoard on the given number of workers
+func generateNGBoard(ctx context.Context, cfg DifficultyConfig, seed int64, tries, repairs, maxComponentSize int, band DifficultyBand, workers int) (<-chan *Minesweeper, <-chan NGProgress) {
+	minesweeperCh := make(chan *Minesweeper, 1)
+	progressCh := make(chan NGProgress, workers)
+
+	type job struct {
+		attempt int
+		seed    int64
+	}
+	type result struct {
+		attempt int
+		m       *Minesweeper
+		err     error
+	}
+
+	go func() {
+		defer close(minesweeperCh)
+		defer close(progressCh)
+
+		// Cancelled by the player, or by the first accepted attempt to
+		// stop handing out the later ones
+		parentCtx := ctx
+		ctx, cancel := context.WithCancel(parentCtx)
+		defer cancel()
+
+		// Earliest attempt accepted (or failed) so far
+		var earliest atomic.Int64
+		earliest.Store(math.MaxInt64)
+		finish := func(attempt int) {
+			for {
+				e := earliest.Load()
+				if int64(attempt) >= e || earliest.CompareAndSwap(e, int64(attempt)) {
+					break
+				}
+			}
+			cancel()
+		}
+
+		// --- Hand out attempt seeds in order ---
+		jobCh := make(chan job)
+		go func() {
+			defer close(jobCh)
+			nextSeed := ngAttemptSeeds(seed)
+			for attempt := 1; attempt <= tries; attempt++ {
+				select {
+				case <-ctx.Done():
+					return
+				case jobCh <- job{attempt, nextSeed()}:
+				}
+			}
+		}()
+
+		// --- Try them on every worker ---
+		resultCh := make(chan result, workers)
+		var wg sync.WaitGroup
+		var total atomic.Int64
+		for worker := range workers {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				attempts := 0
+				for j := range jobCh {
+					if parentCtx.Err() != nil {
+						return
+					}
+					// Only the attempts before the earliest accepted one
+					// can still win
+					if int64(j.attempt) > earliest.Load() {
+						continue
+					}
+					attempts++
+					// Send progress
+					select {
+					case progressCh <- NGProgress{Worker: worker, Attempts: attempts, Total: int(total.Add(1))}:
+					default:
+					}
+
+					m, solvable, err := GenerateRepairedBoard(cfg, j.seed, repairs, maxComponentSize)
+					if err != nil {
+						resultCh <- result{j.attempt, nil, err}
+						finish(j.attempt)
+						return
+					}
+					if acceptsNG(m, solvable, maxComponentSize, band) {
+						resultCh <- result{j.attempt, m, nil}
+						finish(j.attempt)
+						return
+					}
+				}
+			}()
+		}
+		go func() {
+			wg.Wait()
+			close(resultCh)
+		}()
+
+		// --- Keep the earliest solvable attempt ---
+		var best *result
+		for r := range resultCh {
+			if best == nil || r.attempt < best.attempt {
+				best = &r
+			}
+		}
+
+		if best == nil && parentCtx.Err() != nil {
+			// NG board generation is cancelled by player
+			return
+		}
+		if best == nil || best.err != nil {
+			minesweeperCh <- nil
+			return
+		}
+		minesweeperCh <- best.m
+	}()
+
+	return minesweeperCh, progressCh
+}
+
+// NGAttempts goes through the attempts of GenerateNGBoard one after the
+// other and returns the number of the attempt it accepts, 0 when none of
+// the tries is accepted. A bigger tries budget only changes the result
+// when it's 0.
+func NGAttempts(cfg DifficultyConfig, seed int64, tries, repairs, maxComponentSize int, band DifficultyBand) (int, error) {
+	nextSeed := ngAttemptSeeds(seed)
+	for attempt := 1; attempt <= tries; attempt++ {
+		m, solvable, err := GenerateRepairedBoard(cfg, nextSeed(), repairs, maxComponentSize)
+		if err != nil {
+			return 0, err
+		}
+		if acceptsNG(m, solvable, maxComponentSize, band) {
+			return attempt, nil
+		}
+	}
+	return 0, nil
+}
+
+// ngAttemptSeeds returns the board seeds of the NG attempts one after the
+// other: the seed itself, then seeds drawn from it
+func ngAttemptSeeds(seed int64) func() int64 {
+	rng := NewRand(seed)
+	first := true
+	return func() int64 {
+		if first {
+			first = false
+			return seed
+		}
+		return rng.Int63n(MAX_SEED)
+	}
+}
+
+func acceptsNG(m *Minesweeper, solvable bool, maxComponentSize int, band DifficultyBand) bool {
+	return solvable && (band == AnyDifficulty || band.Contains(m.Analyze(maxComponentSize).Difficulty))
+}
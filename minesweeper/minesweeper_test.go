@@ -0,0 +1,109 @@
+package minesweeper
+
+import (
+	"context"
+	"runtime"
+	"slices"
+	"testing"
+)
+
+func TestGenerateNGBoardIsDeterministic(t *testing.T) {
+	if testing.Short() {
+		t.Skip("generates a few dozen expert boards")
+	}
+	// Without repairs, expert boards often take a few attempts, which
+	// leaves room for the workers to race
+	cfg := DifficultyMap["expert"]
+	const tries, repairs, maxComponentSize = 200, 0, 18
+
+	generate := func(seed int64, workers int) *Minesweeper {
+		minesweeperCh, _ := generateNGBoard(context.Background(), cfg, seed, tries, repairs, maxComponentSize, AnyDifficulty, workers)
+		return <-minesweeperCh
+	}
+
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(0))
+	for seed := int64(1); seed <= 3; seed++ {
+		attempt, err := NGAttempts(cfg, seed, tries, repairs, maxComponentSize, AnyDifficulty)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := generate(seed, 1)
+		if (want == nil) != (attempt == 0) {
+			t.Fatalf("seed %d: one worker returned %v, NGAttempts accepted attempt %d", seed, want != nil, attempt)
+		}
+		if want == nil {
+			continue
+		}
+
+		for _, procs := range []int{1, 2, 8} {
+			runtime.GOMAXPROCS(procs)
+			for _, workers := range []int{2, 3, 8} {
+				got := generate(seed, workers)
+				if got == nil {
+					t.Fatalf("seed %d, GOMAXPROCS %d, %d workers: no board, want attempt %d", seed, procs, workers, attempt)
+				}
+				if got.Seed != want.Seed || !slices.Equal(got.BombPositions, want.BombPositions) {
+					t.Errorf("seed %d, GOMAXPROCS %d, %d workers: got the board of seed %d, want seed %d (attempt %d)", seed, procs, workers, got.Seed, want.Seed, attempt)
+				}
+			}
+		}
+	}
+}
+
+func TestNGBoardSeedRebuildsTheBoard(t *testing.T) {
+	cfg := DifficultyMap["intermediate"]
+	const tries, repairs, maxComponentSize = 100, 100, 18
+
+	for seed := int64(1); seed <= 5; seed++ {
+		minesweeperCh, _ := GenerateNGBoard(context.Background(), cfg, seed, tries, repairs, maxComponentSize, AnyDifficulty)
+		m := <-minesweeperCh
+		if m == nil {
+			t.Fatalf("seed %d: no NG board", seed)
+		}
+		minesweeperCh, _ = GenerateNGBoard(context.Background(), cfg, m.Seed, tries, repairs, maxComponentSize, AnyDifficulty)
+		rebuilt := <-minesweeperCh
+		if rebuilt == nil || rebuilt.Seed != m.Seed || !slices.Equal(rebuilt.BombPositions, m.BombPositions) {
+			t.Errorf("seed %d: the board's seed %d doesn't rebuild it", seed, m.Seed)
+		}
+	}
+}
+
+func TestGenerateNGBoardWithinBudget(t *testing.T) {
+	if testing.Short() {
+		t.Skip("generates a few hundred expert boards")
+	}
+	// Lowered budgets get attempts of this seed rejected that the usual
+	// ones accept, so acceptance goes through searches running out
+	cfg := DifficultyMap["expert"]
+	const seed, tries, repairs, maxComponentSize = 12, 200, 0, 18
+	full, err := NGAttempts(cfg, seed, tries, repairs, maxComponentSize, AnyDifficulty)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lowerBudgets(t)
+	attempt, err := NGAttempts(cfg, seed, tries, repairs, maxComponentSize, AnyDifficulty)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if attempt == full || attempt == 0 {
+		t.Fatalf("attempt %d accepted with the lowered budgets, %d with the usual ones", attempt, full)
+	}
+
+	nextSeed := ngAttemptSeeds(seed)
+	var want int64
+	for range attempt {
+		want = nextSeed()
+	}
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(0))
+	for _, procs := range []int{1, 2, 8} {
+		runtime.GOMAXPROCS(procs)
+		for _, workers := range []int{1, 3, 8} {
+			for range 3 {
+				minesweeperCh, _ := generateNGBoard(context.Background(), cfg, seed, tries, repairs, maxComponentSize, AnyDifficulty, workers)
+				if m := <-minesweeperCh; m == nil || m.Seed != want {
+					t.Fatalf("GOMAXPROCS %d, %d workers: didn't get the board of attempt %d", procs, workers, attempt)
+				}
+			}
+		}
+	}
+}
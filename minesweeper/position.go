@@ -0,0 +1,131 @@
+package minesweeper
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Cell values of a Position that aren't revealed numbers
+const (
+	UNKNOWN int = -2
+	FLAGGED int = -3
+)
+
+// Position is what a player can see of a board: revealed numbers, placed
+// flags and unknown cells. A revealed bomb is kept as BOMB. BombCount is
+// the total number of bombs on the board, 0 when it isn't known.
+type Position struct {
+	Rows      int
+	Cols      int
+	BombCount int
+	Cells     [][]int
+}
+
+// Position returns the player's view of the board
+func (m *Minesweeper) Position() Position {
+	p := newPosition(m.Rows, m.Cols, m.BombCount)
+	for row := range m.Rows {
+		for col := range m.Cols {
+			cell := m.Grid[row][col]
+			if cell.Revealed {
+				p.Cells[row][col] = cell.Value
+			} else if cell.Flagged {
+				p.Cells[row][col] = FLAGGED
+			}
+		}
+	}
+	return p
+}
+
+// positionFrom builds the view of a player who revealed and flagged
+// exactly the given cells
+func (m *Minesweeper) positionFrom(revealed, flagged map[[2]int]struct{}) Position {
+	p := newPosition(m.Rows, m.Cols, m.BombCount)
+	for pos := range revealed {
+		p.Cells[pos[0]][pos[1]] = m.Grid[pos[0]][pos[1]].Value
+	}
+	for pos := range flagged {
+		p.Cells[pos[0]][pos[1]] = FLAGGED
+	}
+	return p
+}
+
+func newPosition(rows, cols, bombCount int) Position {
+	p := Position{
+		Rows:      rows,
+		Cols:      cols,
+		BombCount: bombCount,
+		Cells:     make([][]int, rows),
+	}
+	for row := range p.Cells {
+		p.Cells[row] = make([]int, cols)
+		for col := range p.Cells[row] {
+			p.Cells[row][col] = UNKNOWN
+		}
+	}
+	return p
+}
+
+func (p Position) isOutOfBounds(row, col int) bool {
+	return row < 0 || row >= p.Rows || col < 0 || col >= p.Cols
+}
+
+func (p Position) getNeighborsOf(row, col int) [][2]int {
+	neighbors := make([][2]int, 0, 8)
+	for _, direction := range directions {
+		newRow := row + direction[0]
+		newCol := col + direction[1]
+		if !p.isOutOfBounds(newRow, newCol) {
+			neighbors = append(neighbors, [2]int{newRow, newCol})
+		}
+	}
+	return neighbors
+}
+
+var ErrInvalidPosition = errors.New("position is invalid")
+
+// ParsePosition reads a position in the text notation: one line per row,
+// a digit for a revealed number, '.' for an unknown cell and '*' or 'F'
+// for a flag. Spaces between cells are ignored, and so are blank lines
+// and lines starting with '#'. The notation has no bomb count, so
+// BombCount is left at 0.
+func ParsePosition(r io.Reader) (Position, error) {
+	var cells [][]int
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		row := make([]int, 0, len(text))
+		for _, c := range text {
+			switch {
+			case c == ' ' || c == '\t':
+			case '0' <= c && c <= '8':
+				row = append(row, int(c-'0'))
+			case c == '.':
+				row = append(row, UNKNOWN)
+			case c == '*' || c == 'F':
+				row = append(row, FLAGGED)
+			default:
+				return Position{}, fmt.Errorf("%w: line %d: unexpected %q", ErrInvalidPosition, line, c)
+			}
+		}
+		if len(cells) > 0 && len(row) != len(cells[0]) {
+			return Position{}, fmt.Errorf("%w: line %d has %d cells, expected %d", ErrInvalidPosition, line, len(row), len(cells[0]))
+		}
+		cells = append(cells, row)
+	}
+	if err := scanner.Err(); err != nil {
+		return Position{}, err
+	}
+	if len(cells) == 0 {
+		return Position{}, fmt.Errorf("%w: no cells", ErrInvalidPosition)
+	}
+
+	return Position{Rows: len(cells), Cols: len(cells[0]), Cells: cells}, nil
+}
@@ -0,0 +1,322 @@
+package minesweeper
+
+import (
+	"math"
+	"math/big"
+	"slices"
+)
+
+// Probabilities returns the bomb probability of every unknown cell of the
+// position, the interior cells touching no number included. Cells the
+// solver decides get 0 or 1. When BombCount is known, the assignments of
+// every component are weighted by the number of ways to place the bombs
+// left on the other components and the interior, which makes the result
+// exact as long as every component fits in maxComponentSize. Without it,
+// each component is rated on its own and the interior cells are left out.
+// It returns false when the position is inconsistent.
+func Probabilities(p Position, maxComponentSize int) (map[[2]int]float64, bool) {
+	s := newPositionSolver(p)
+	if !s.solve(maxComponentSize) {
+		return nil, false
+	}
+	probabilities, ok := s.probabilities(maxComponentSize)
+	if !ok {
+		return nil, false
+	}
+	for pos := range s.safe {
+		probabilities[pos] = 0
+	}
+	for pos := range s.mines {
+		if p.Cells[pos[0]][pos[1]] == UNKNOWN {
+			probabilities[pos] = 1
+		}
+	}
+	return probabilities, true
+}
+
+// probabilities rates the bomb probability of every undecided cell once
+// the solver is done, as described on Probabilities. Components too large
+// to count get each cell estimated from its most pessimistic constraint.
+// When weighing the others, they're taken to hold as many bombs as
+// reserveBombs picks, their estimates being scaled to that count. It
+// returns false when the position is inconsistent.
+func (s *positionSolver) probabilities(maxComponentSize int) (map[[2]int]float64, bool) {
+	constraints, ok := s.constraints()
+	if !ok {
+		return nil, false
+	}
+
+	// --- Count the assignments of each component ---
+	probabilities := make(map[[2]int]float64)
+	components := frontierComponents(constraints)
+	counted := make([]*componentSolutions, 0, len(components))
+	countedCells := make([][][2]int, 0, len(components))
+	estimatedCells := make([][2]int, 0)
+	estimatedBombs := 0.
+	for _, component := range components {
+		var cs *componentSolutions
+		if len(component) <= maxComponentSize {
+			cs = newComponentSearch(component, constraints).count(countNodeBudget)
+		}
+		if cs == nil {
+			for _, constraint := range constraints {
+				p := float64(constraint.RemainingValue) / float64(len(constraint.UnknownNeighbors))
+				for _, u := range constraint.UnknownNeighbors {
+					if slices.Contains(component, u) {
+						probabilities[u] = max(probabilities[u], p)
+					}
+				}
+			}
+			for _, u := range component {
+				estimatedBombs += probabilities[u]
+			}
+			estimatedCells = append(estimatedCells, component...)
+			continue
+		}
+		if cs.count() == 0 {
+			return nil, false
+		}
+		counted = append(counted, cs)
+		countedCells = append(countedCells, component)
+	}
+
+	// --- Without the bomb count, every assignment weighs the same ---
+	if s.p.BombCount <= 0 {
+		for ci, cs := range counted {
+			total := cs.count()
+			for i, u := range countedCells[ci] {
+				probabilities[u] = float64(cs.bombsOn(i)) / float64(total)
+			}
+		}
+		return probabilities, true
+	}
+
+	inFrontier := make(map[[2]int]struct{})
+	for _, component := range components {
+		for _, u := range component {
+			inFrontier[u] = struct{}{}
+		}
+	}
+	interior := make([][2]int, 0)
+	for row := range s.p.Rows {
+		for col := range s.p.Cols {
+			pos := [2]int{row, col}
+			if _, ok := inFrontier[pos]; !ok && s.isUnknown(pos) {
+				interior = append(interior, pos)
+			}
+		}
+	}
+	available := s.p.BombCount - len(s.mines)
+	if available < 0 {
+		return nil, false
+	}
+
+	// Counts get too large for any fixed size integer on big boards, so
+	// they're kept exact with math/big
+	totals := make([][]*big.Int, len(counted))
+	for ci, cs := range counted {
+		totals[ci] = make([]*big.Int, len(cs.total))
+		for k, t := range cs.total {
+			totals[ci][k] = big.NewInt(int64(t))
+		}
+	}
+
+	// --- Bombs held by the components too large to count ---
+	all := unitCounts(available)
+	for ci := range totals {
+		all = convolveCounts(all, totals[ci], available)
+	}
+	reserved, ok := reserveBombs(all, len(interior), len(estimatedCells), available, estimatedBombs)
+	if !ok {
+		return nil, false
+	}
+	spreadBombs(probabilities, estimatedCells, reserved)
+	bombsLeft := available - reserved
+
+	// --- Weigh the assignments by the ways to place the other bombs ---
+	// ways[m] is the number of ways to put m bombs on the interior cells
+	ways := make([]*big.Int, bombsLeft+1)
+	for m := range ways {
+		ways[m] = new(big.Int)
+		if m <= len(interior) {
+			ways[m].Binomial(int64(len(interior)), int64(m))
+		}
+	}
+
+	// Assignments of the components before (prefix) and after (suffix)
+	// each one, by the bombs they use
+	n := len(counted)
+	prefix := make([][]*big.Int, n+1)
+	prefix[0] = unitCounts(bombsLeft)
+	for ci := range n {
+		prefix[ci+1] = convolveCounts(prefix[ci], totals[ci], bombsLeft)
+	}
+	suffix := make([][]*big.Int, n+1)
+	suffix[n] = unitCounts(bombsLeft)
+	for ci := n - 1; ci >= 0; ci-- {
+		suffix[ci] = convolveCounts(suffix[ci+1], totals[ci], bombsLeft)
+	}
+
+	// weigh sums counts[s] times the ways to place the remaining
+	// bombsLeft-used-s bombs on the interior, scaled by scale(s)
+	weigh := func(counts []*big.Int, used int, scale func(s int) int64) *big.Int {
+		sum := new(big.Int)
+		term := new(big.Int)
+		for s, c := range counts {
+			if c.Sign() == 0 || used+s > bombsLeft {
+				continue
+			}
+			term.Mul(c, ways[bombsLeft-used-s])
+			if scale != nil {
+				term.Mul(term, big.NewInt(scale(s)))
+			}
+			sum.Add(sum, term)
+		}
+		return sum
+	}
+	ratio := func(num, den *big.Int) float64 {
+		f, _ := new(big.Rat).SetFrac(num, den).Float64()
+		return f
+	}
+
+	total := weigh(prefix[n], 0, nil)
+	if total.Sign() == 0 {
+		return nil, false
+	}
+
+	// --- Component cells ---
+	for ci, cs := range counted {
+		others := convolveCounts(prefix[ci], suffix[ci+1], bombsLeft)
+		mineWeights := make([]*big.Int, len(countedCells[ci]))
+		for i := range mineWeights {
+			mineWeights[i] = new(big.Int)
+		}
+		term := new(big.Int)
+		for k := range cs.total {
+			if cs.total[k] == 0 || k > bombsLeft {
+				continue
+			}
+			weight := weigh(others, k, nil)
+			for i := range countedCells[ci] {
+				term.Mul(weight, big.NewInt(int64(cs.bombs[k][i])))
+				mineWeights[i].Add(mineWeights[i], term)
+			}
+		}
+		for i, u := range countedCells[ci] {
+			probabilities[u] = ratio(mineWeights[i], total)
+		}
+	}
+
+	// --- Interior cells share the bombs the frontier leaves ---
+	if len(interior) > 0 {
+		interiorBombs := weigh(prefix[n], 0, func(s int) int64 {
+			return int64(bombsLeft - s)
+		})
+		p := ratio(interiorBombs, total) / float64(len(interior))
+		for _, pos := range interior {
+			probabilities[pos] = p
+		}
+	}
+
+	return probabilities, true
+}
+
+// reserveBombs picks how many bombs the components too large to count
+// hold: the count closest to their estimate among the ones leaving the
+// counted components and the interior cells a valid layout, counts being
+// the assignments of the counted components by bombs used. It returns
+// false when no count does, which makes the position inconsistent
+// whatever the large components hold.
+func reserveBombs(counts []*big.Int, interiorCells, estimatedCells, available int, estimate float64) (int, bool) {
+	reserved, found := 0, false
+	for r := range min(estimatedCells, available) + 1 {
+		fits := false
+		for used, c := range counts {
+			left := available - r - used
+			if c.Sign() != 0 && 0 <= left && left <= interiorCells {
+				fits = true
+				break
+			}
+		}
+		if fits && (!found || math.Abs(float64(r)-estimate) < math.Abs(float64(reserved)-estimate)) {
+			reserved, found = r, true
+		}
+	}
+	return reserved, found
+}
+
+// spreadBombs scales the probabilities of cells for them to add up to
+// bombs, in proportion to their estimates. Cells it would put past 1 are
+// capped, the rest being scaled again to make up for them. Cells all
+// estimated at 0 share the bombs evenly.
+func spreadBombs(probabilities map[[2]int]float64, cells [][2]int, bombs int) {
+	open := slices.Clone(cells)
+	left := float64(bombs)
+	for len(open) > 0 {
+		sum := 0.
+		for _, u := range open {
+			sum += probabilities[u]
+		}
+		scaled := func(u [2]int) float64 {
+			if sum > 0 {
+				return probabilities[u] * left / sum
+			}
+			return left / float64(len(open))
+		}
+
+		uncapped := make([][2]int, 0, len(open))
+		for _, u := range open {
+			if scaled(u) < 1 {
+				uncapped = append(uncapped, u)
+			}
+		}
+		if len(uncapped) == len(open) {
+			for _, u := range open {
+				probabilities[u] = scaled(u)
+			}
+			return
+		}
+		for _, u := range open {
+			if scaled(u) >= 1 {
+				probabilities[u] = 1
+			}
+		}
+		left -= float64(len(open) - len(uncapped))
+		open = uncapped
+	}
+}
+
+// unitCounts is the count vector of using no component: one way with no
+// bomb
+func unitCounts(maxBombs int) []*big.Int {
+	counts := make([]*big.Int, maxBombs+1)
+	for i := range counts {
+		counts[i] = new(big.Int)
+	}
+	counts[0].SetInt64(1)
+	return counts
+}
+
+// convolveCounts combines two count vectors indexed by bombs used, the
+// result being capped at maxBombs
+func convolveCounts(a, b []*big.Int, maxBombs int) []*big.Int {
+	res := make([]*big.Int, maxBombs+1)
+	for i := range res {
+		res[i] = new(big.Int)
+	}
+	term := new(big.Int)
+	for i, x := range a {
+		if x.Sign() == 0 {
+			continue
+		}
+		for j, y := range b {
+			if i+j > maxBombs {
+				break
+			}
+			if y.Sign() != 0 {
+				res[i+j].Add(res[i+j], term.Mul(x, y))
+			}
+		}
+	}
+	return res
+}
@@ -0,0 +1,200 @@
+package minesweeper
+
+import (
+	"math"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+// bruteProbabilities goes through every layout of the bombs left over the
+// unknown cells. It returns false when none matches the numbers.
+func bruteProbabilities(p Position) (map[[2]int]float64, bool) {
+	bombs := make([][]bool, p.Rows)
+	unknown := make([][2]int, 0)
+	bombsLeft := p.BombCount
+	for row := range p.Rows {
+		bombs[row] = make([]bool, p.Cols)
+		for col := range p.Cols {
+			switch p.Cells[row][col] {
+			case UNKNOWN:
+				unknown = append(unknown, [2]int{row, col})
+			case FLAGGED:
+				bombs[row][col] = true
+				bombsLeft--
+			}
+		}
+	}
+
+	matches := func() bool {
+		for row := range p.Rows {
+			for col := range p.Cols {
+				if p.Cells[row][col] < 0 {
+					continue
+				}
+				n := 0
+				for _, neighbor := range p.getNeighborsOf(row, col) {
+					if bombs[neighbor[0]][neighbor[1]] {
+						n++
+					}
+				}
+				if n != p.Cells[row][col] {
+					return false
+				}
+			}
+		}
+		return true
+	}
+
+	layouts := 0
+	counts := make([]int, len(unknown))
+	var place func(from, left int)
+	place = func(from, left int) {
+		if left == 0 {
+			if matches() {
+				layouts++
+				for i, u := range unknown {
+					if bombs[u[0]][u[1]] {
+						counts[i]++
+					}
+				}
+			}
+			return
+		}
+		for i := from; i <= len(unknown)-left; i++ {
+			u := unknown[i]
+			bombs[u[0]][u[1]] = true
+			place(i+1, left-1)
+			bombs[u[0]][u[1]] = false
+		}
+	}
+	if bombsLeft >= 0 {
+		place(0, bombsLeft)
+	}
+	if layouts == 0 {
+		return nil, false
+	}
+
+	probabilities := make(map[[2]int]float64, len(unknown))
+	for i, u := range unknown {
+		probabilities[u] = float64(counts[i]) / float64(layouts)
+	}
+	return probabilities, true
+}
+
+func TestProbabilitiesMatchBruteForce(t *testing.T) {
+	checked := 0
+	for seed := int64(1); seed <= 200; seed++ {
+		rng := rand.New(rand.NewSource(seed))
+		cfg := DifficultyConfig{Rows: 4 + rng.Intn(2), Cols: 4 + rng.Intn(2), BombCount: 3 + rng.Intn(4)}
+		m, err := GenerateBoardWithStartCell(cfg, seed)
+		if err != nil {
+			t.Fatal(err)
+		}
+		m.Play(ActionReveal, m.StartCellPosition[0], m.StartCellPosition[1])
+		for range rng.Intn(3) {
+			row, col := rng.Intn(cfg.Rows), rng.Intn(cfg.Cols)
+			if m.Grid[row][col].Value != BOMB {
+				m.Play(ActionReveal, row, col)
+			}
+		}
+		if m.IsGameOver {
+			continue
+		}
+
+		p := m.Position()
+		want, _ := bruteProbabilities(p)
+		got, ok := Probabilities(p, 18)
+		if !ok {
+			t.Errorf("seed %d: the position of a real board is reported inconsistent", seed)
+			continue
+		}
+		if len(got) != len(want) {
+			t.Errorf("seed %d: got %d cells, want %d", seed, len(got), len(want))
+		}
+		for pos, w := range want {
+			if g, ok := got[pos]; !ok || math.Abs(g-w) > 1e-9 {
+				t.Errorf("seed %d: cell %v got %v, want %v", seed, pos, g, w)
+			}
+		}
+		// Estimating every component mustn't make it inconsistent
+		if _, ok := Probabilities(p, 2); !ok {
+			t.Errorf("seed %d: the position is reported inconsistent with a size limit of 2", seed)
+		}
+		checked++
+	}
+	if checked < 100 {
+		t.Fatalf("only %d positions checked", checked)
+	}
+}
+
+func TestProbabilitiesLargeComponent(t *testing.T) {
+	// One component of 21 cells, past the solver's usual size limit
+	p, err := ParsePosition(strings.NewReader("F....2.\n..3....\n.1..4..\n.......\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	p.BombCount = 7
+	want, ok := bruteProbabilities(p)
+	if !ok {
+		t.Fatal("no layout matches the position")
+	}
+
+	for _, maxComponentSize := range []int{21, 18, 8} {
+		got, ok := Probabilities(p, maxComponentSize)
+		if !ok {
+			t.Errorf("size limit %d: the position is reported inconsistent", maxComponentSize)
+			continue
+		}
+		if len(got) != len(want) {
+			t.Errorf("size limit %d: got %d cells, want %d", maxComponentSize, len(got), len(want))
+		}
+
+		// Counted exactly when the component fits, estimated otherwise
+		sum := 0.
+		for pos, w := range want {
+			g := got[pos]
+			if maxComponentSize >= 21 && math.Abs(g-w) > 1e-9 {
+				t.Errorf("size limit %d: cell %v got %v, want %v", maxComponentSize, pos, g, w)
+			}
+			if g < 0 || g > 1 {
+				t.Errorf("size limit %d: cell %v got %v, out of [0, 1]", maxComponentSize, pos, g)
+			}
+			sum += g
+		}
+		if math.Abs(sum-6) > 1e-9 {
+			t.Errorf("size limit %d: the probabilities add up to %v bombs, want the 6 left", maxComponentSize, sum)
+		}
+	}
+}
+
+func TestSpreadBombs(t *testing.T) {
+	tests := []struct {
+		name      string
+		estimates []float64
+		bombs     int
+		want      []float64
+	}{
+		{"scaled", []float64{0.2, 0.4, 0.4, 0.5, 0.5}, 3, []float64{0.3, 0.6, 0.6, 0.75, 0.75}},
+		{"capped", []float64{0.9, 0.9, 0.1, 0.1}, 3, []float64{1, 1, 0.5, 0.5}},
+		{"capped twice", []float64{0.8, 0.4, 0.2, 0.1, 0.1}, 3, []float64{1, 1, 0.5, 0.25, 0.25}},
+		{"all estimated at 0", []float64{0, 0, 0, 0}, 1, []float64{0.25, 0.25, 0.25, 0.25}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			probabilities := make(map[[2]int]float64)
+			cells := make([][2]int, len(tt.estimates))
+			for i, estimate := range tt.estimates {
+				cells[i] = [2]int{0, i}
+				probabilities[cells[i]] = estimate
+			}
+			spreadBombs(probabilities, cells, tt.bombs)
+			for i, u := range cells {
+				if math.Abs(probabilities[u]-tt.want[i]) > 1e-9 {
+					t.Errorf("cell %d: got %v, want %v", i, probabilities[u], tt.want[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,116 @@
+package minesweeper
+
+import "math/rand"
+
+// GenerateRepairedBoard generates the board of GenerateBoardWithStartCell
+// and, for as long as DeterministicSolve gets stuck on it (at most repairs
+// times), moves one bomb out of the region where the solver got stuck
+// before solving it again. Bombs move to a uniformly random cell, away from
+// the revealed region whenever possible and never into the safety zone
+// around the start cell, so the bomb count and the feel of the layout are
+// kept. The same arguments always give the same board. It reports whether
+// the final board is solvable without guessing.
+func GenerateRepairedBoard(cfg DifficultyConfig, seed int64, repairs, maxComponentSize int) (*Minesweeper, bool, error) {
+	m, err := GenerateBoardWithStartCell(cfg, seed)
+	if err != nil {
+		return nil, false, err
+	}
+
+	// Repairs draw from their own stream, the initial layout stays the
+	// one of the seed
+	rng := NewRand(^seed)
+
+	for repair := 0; ; repair++ {
+		solvable, revealed, flagged := m.DeterministicSolve(maxComponentSize)
+		if solvable || repair == repairs {
+			return m, solvable, nil
+		}
+
+		from, to, ok := m.pickRepair(rng, revealed, flagged)
+		if !ok {
+			return m, false, nil
+		}
+
+		bombPositions := make([][2]int, 0, len(m.BombPositions))
+		for _, pos := range m.BombPositions {
+			if pos == from {
+				pos = to
+			}
+			bombPositions = append(bombPositions, pos)
+		}
+		repaired, err := NewBoard(m.Rows, m.Cols, bombPositions, m.StartCellPosition)
+		if err != nil {
+			return nil, false, err
+		}
+		repaired.Seed = m.Seed
+		m = repaired
+	}
+}
+
+// pickRepair picks a bomb the solver got stuck on (an unresolved bomb next
+// to the revealed region) and a cell to move it to
+func (m *Minesweeper) pickRepair(rng *rand.Rand, revealed, flagged map[[2]int]struct{}) (from, to [2]int, ok bool) {
+	nextToRevealed := func(pos [2]int) bool {
+		for _, neighbor := range m.getNeighborsOf(pos[0], pos[1]) {
+			if _, ok := revealed[neighbor]; ok {
+				return true
+			}
+		}
+		return false
+	}
+	pick := func(cells [][2]int) [2]int {
+		return cells[rng.Intn(len(cells))]
+	}
+
+	// --- Bomb to move: a stuck frontier bomb, or any unresolved one ---
+	stuck := make([][2]int, 0)
+	unresolved := make([][2]int, 0)
+	for _, pos := range m.BombPositions {
+		if _, ok := flagged[pos]; ok {
+			continue
+		}
+		unresolved = append(unresolved, pos)
+		if nextToRevealed(pos) {
+			stuck = append(stuck, pos)
+		}
+	}
+	switch {
+	case len(stuck) > 0:
+		from = pick(stuck)
+	case len(unresolved) > 0:
+		from = pick(unresolved)
+	default:
+		return from, to, false
+	}
+
+	// --- Destination: away from the revealed region, or anywhere else
+	// outside the stuck frontier ---
+	away := make([][2]int, 0)
+	elsewhere := make([][2]int, 0)
+	start := m.StartCellPosition
+	for row := range m.Rows {
+		for col := range m.Cols {
+			pos := [2]int{row, col}
+			if m.Grid[row][col].Value == BOMB || isAdjacent(start[0], start[1], row, col) {
+				continue
+			}
+			_, isRevealed := revealed[pos]
+			switch {
+			case !isRevealed && !nextToRevealed(pos):
+				away = append(away, pos)
+			case isRevealed:
+				elsewhere = append(elsewhere, pos)
+			}
+		}
+	}
+	switch {
+	case len(away) > 0:
+		to = pick(away)
+	case len(elsewhere) > 0:
+		to = pick(elsewhere)
+	default:
+		return from, to, false
+	}
+
+	return from, to, true
+}
@@ -0,0 +1,194 @@
+package minesweeper
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+// REPLAY_VERSION is bumped whenever the replay layout changes
+const REPLAY_VERSION = 1
+
+var (
+	ErrCorruptReplay            = errors.New("replay is corrupt")
+	ErrUnsupportedReplayVersion = errors.New("replay version is not supported")
+)
+
+type ActionKind string
+
+const (
+	ActionReveal ActionKind = "reveal"
+	ActionChord  ActionKind = "chord"
+	ActionFlag   ActionKind = "flag"
+	ActionUndo   ActionKind = "undo"
+	ActionRedo   ActionKind = "redo"
+)
+
+// Action is one move of the player, At being the game clock when it was
+// played
+type Action struct {
+	Kind ActionKind    `json:"kind"`
+	Row  int           `json:"row"`
+	Col  int           `json:"col"`
+	At   time.Duration `json:"atMs"`
+}
+
+// Play plays a move like the player would, counting it as a click and
+// recording it for replays and undo. Undo and redo ignore the cell and
+// aren't clicks. It reports whether any cell got revealed (or, for undo
+// and redo, whether there was a move to take back or play again).
+func (m *Minesweeper) Play(kind ActionKind, row, col int) bool {
+	action := Action{Kind: kind, Row: row, Col: col, At: m.ElapsedTime()}
+
+	switch kind {
+	case ActionUndo, ActionRedo:
+		ok := m.Undo
+		if kind == ActionRedo {
+			ok = m.Redo
+		}
+		if !ok() {
+			return false
+		}
+		m.Actions = append(m.Actions, action)
+		return true
+	}
+
+	if m.IsGameOver || m.IsOutOfBounds(row, col) {
+		return false
+	}
+	m.Clicks++
+	m.Actions = append(m.Actions, action)
+
+	cs := &ChangeSet{}
+	m.changes = cs
+	defer func() {
+		m.changes = nil
+		if len(cs.Revealed) == 0 && len(cs.Toggled) == 0 {
+			return
+		}
+		cs.EndedGame, cs.Won = m.IsGameOver, m.IsWon
+		m.history = append(m.history, *cs)
+		m.future = nil
+	}()
+
+	switch kind {
+	case ActionReveal:
+		return m.Reveal(row, col, true)
+	case ActionChord:
+		return m.Chord(row, col)
+	case ActionFlag:
+		m.Flag(row, col)
+	}
+	return false
+}
+
+// Replay is a complete game: the board layout and every move played on it
+type Replay struct {
+	Version       int      `json:"version"`
+	Rows          int      `json:"rows"`
+	Cols          int      `json:"cols"`
+	Seed          int64    `json:"seed"`
+	NG            bool     `json:"ng"`
+	BombPositions [][2]int `json:"bombPositions"`
+	StartCell     [2]int   `json:"startCell"`
+	Actions       []Action `json:"actions"`
+	Practice      bool     `json:"practice"`
+	Won           bool     `json:"won"`
+}
+
+// Replay captures the game played so far
+func (m *Minesweeper) Replay() Replay {
+	return Replay{
+		Version:       REPLAY_VERSION,
+		Rows:          m.Rows,
+		Cols:          m.Cols,
+		Seed:          m.Seed,
+		NG:            m.NG,
+		BombPositions: m.BombPositions,
+		StartCell:     m.StartCellPosition,
+		Actions:       append([]Action(nil), m.Actions...),
+		Practice:      m.Practice,
+		Won:           m.IsWon,
+	}
+}
+
+// NewBoard rebuilds the untouched board the replay was played on
+func (r *Replay) NewBoard() (*Minesweeper, error) {
+	m, err := NewBoard(r.Rows, r.Cols, r.BombPositions, r.StartCell)
+	if err != nil {
+		return nil, err
+	}
+	m.Seed = r.Seed
+	m.NG = r.NG
+	m.Practice = r.Practice
+	return m, nil
+}
+
+// Duration is the game clock at the last move
+func (r *Replay) Duration() time.Duration {
+	if len(r.Actions) == 0 {
+		return 0
+	}
+	return r.Actions[len(r.Actions)-1].At
+}
+
+// MarshalJSON stores the action time in milliseconds
+func (a Action) MarshalJSON() ([]byte, error) {
+	type action Action
+	aa := action(a)
+	aa.At = a.At / time.Millisecond
+	return json.Marshal(aa)
+}
+
+func (a *Action) UnmarshalJSON(data []byte) error {
+	type action Action
+	var aa action
+	if err := json.Unmarshal(data, &aa); err != nil {
+		return err
+	}
+	*a = Action(aa)
+	a.At *= time.Millisecond
+	return nil
+}
+
+func (r *Replay) Save(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
+
+// LoadReplay reads a replay written by Replay.Save and checks that every
+// move fits the board
+func LoadReplay(rd io.Reader) (*Replay, error) {
+	var r Replay
+	if err := json.NewDecoder(rd).Decode(&r); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrCorruptReplay, err)
+	}
+	if r.Version != REPLAY_VERSION {
+		return nil, fmt.Errorf("%w: got version %d, expected %d", ErrUnsupportedReplayVersion, r.Version, REPLAY_VERSION)
+	}
+
+	m, err := r.NewBoard()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrCorruptReplay, err)
+	}
+	var last time.Duration
+	for i, a := range r.Actions {
+		switch a.Kind {
+		case ActionReveal, ActionChord, ActionFlag, ActionUndo, ActionRedo:
+		default:
+			return nil, fmt.Errorf("%w: move %d has unknown kind %q", ErrCorruptReplay, i+1, a.Kind)
+		}
+		if m.IsOutOfBounds(a.Row, a.Col) {
+			return nil, fmt.Errorf("%w: move %d at %d,%d is out of bounds", ErrCorruptReplay, i+1, a.Row, a.Col)
+		}
+		if a.At < last {
+			return nil, fmt.Errorf("%w: move %d goes back in time", ErrCorruptReplay, i+1)
+		}
+		last = a.At
+	}
+
+	return &r, nil
+}
@@ -0,0 +1,146 @@
+package minesweeper
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+// SAVE_VERSION is bumped whenever the saved game layout changes. Version
+// 2 added hints used, clicks, actions, practice mode and the unranked
+// mark; version 1 saves lack the actions a replay needs, so they're
+// rejected rather than migrated.
+const SAVE_VERSION = 2
+
+var (
+	ErrCorruptSave            = errors.New("saved game is corrupt")
+	ErrUnsupportedSaveVersion = errors.New("saved game version is not supported")
+)
+
+type savedGame struct {
+	Version       int      `json:"version"`
+	Rows          int      `json:"rows"`
+	Cols          int      `json:"cols"`
+	Seed          int64    `json:"seed"`
+	NG            bool     `json:"ng"`
+	BombPositions [][2]int `json:"bombPositions"`
+	StartCell     [2]int   `json:"startCell"`
+	Revealed      [][2]int `json:"revealed"`
+	Flagged       [][2]int `json:"flagged"`
+	ElapsedMs     int64    `json:"elapsedMs"`
+	HintsUsed     int      `json:"hintsUsed"`
+	Clicks        int      `json:"clicks"`
+	Actions       []Action `json:"actions"`
+	Practice      bool     `json:"practice"`
+	Unranked      bool     `json:"unranked"`
+}
+
+// Save writes the whole game state as versioned JSON. The clock
+// keeps running, the saved elapsed time is the time played so far.
+func (m *Minesweeper) Save(w io.Writer) error {
+	sg := savedGame{
+		Version:       SAVE_VERSION,
+		Rows:          m.Rows,
+		Cols:          m.Cols,
+		Seed:          m.Seed,
+		NG:            m.NG,
+		BombPositions: m.BombPositions,
+		StartCell:     m.StartCellPosition,
+		ElapsedMs:     m.ElapsedTime().Milliseconds(),
+		HintsUsed:     m.HintsUsed,
+		Clicks:        m.Clicks,
+		Actions:       m.Actions,
+		Practice:      m.Practice,
+		Unranked:      m.Unranked,
+	}
+	sg.Revealed, sg.Flagged = m.revealedAndFlagged()
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(sg)
+}
+
+// Load restores a game written by Save. The clock of the restored
+// game is stopped, call StartClock once the player resumes. Moves played
+// before saving can't be undone anymore.
+func Load(r io.Reader) (*Minesweeper, error) {
+	var sg savedGame
+	if err := json.NewDecoder(r).Decode(&sg); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrCorruptSave, err)
+	}
+	if sg.Version != SAVE_VERSION {
+		return nil, fmt.Errorf("%w: got version %d, expected %d", ErrUnsupportedSaveVersion, sg.Version, SAVE_VERSION)
+	}
+
+	m, err := NewBoard(sg.Rows, sg.Cols, sg.BombPositions, sg.StartCell)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrCorruptSave, err)
+	}
+	m.Seed = sg.Seed
+	m.NG = sg.NG
+	m.Elapsed = time.Duration(sg.ElapsedMs) * time.Millisecond
+	m.HintsUsed = sg.HintsUsed
+	m.Clicks = sg.Clicks
+	m.Actions = sg.Actions
+	m.Practice = sg.Practice
+	m.Unranked = sg.Unranked
+
+	if err := m.restoreCells(sg.Revealed, sg.Flagged); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrCorruptSave, err)
+	}
+
+	return m, nil
+}
+
+// revealedAndFlagged lists the revealed and the flagged cells, in
+// reading order
+func (m *Minesweeper) revealedAndFlagged() (revealed, flagged [][2]int) {
+	revealed, flagged = make([][2]int, 0), make([][2]int, 0)
+	for r := range m.Grid {
+		for c, cell := range m.Grid[r] {
+			if cell.Revealed {
+				revealed = append(revealed, [2]int{r, c})
+			}
+			if cell.Flagged {
+				flagged = append(flagged, [2]int{r, c})
+			}
+		}
+	}
+	return revealed, flagged
+}
+
+// restoreCells reveals and flags the given cells on a fresh board, without
+// flooding, and sets the game over when they end it
+func (m *Minesweeper) restoreCells(revealed, flagged [][2]int) error {
+	for _, pos := range revealed {
+		if m.IsOutOfBounds(pos[0], pos[1]) {
+			return fmt.Errorf("revealed cell %v is out of bounds", pos)
+		}
+		cell := &m.Grid[pos[0]][pos[1]]
+		if cell.Revealed {
+			continue
+		}
+		cell.Revealed = true
+		if cell.Value == BOMB {
+			m.IsGameOver = true
+		} else {
+			m.RevealedCount++
+		}
+	}
+	for _, pos := range flagged {
+		if m.IsOutOfBounds(pos[0], pos[1]) {
+			return fmt.Errorf("flagged cell %v is out of bounds", pos)
+		}
+		if m.Grid[pos[0]][pos[1]].Revealed {
+			return fmt.Errorf("flagged cell %v is already revealed", pos)
+		}
+		m.Grid[pos[0]][pos[1]].Flagged = true
+	}
+	if !m.IsGameOver && m.RevealedCount == m.Rows*m.Cols-m.BombCount {
+		m.IsGameOver = true
+		m.IsWon = true
+	}
+	return nil
+}
@@ -0,0 +1,316 @@
+package minesweeper
+
+import (
+	"maps"
+	"slices"
+)
+
+// Search budgets (in visited search nodes) keeping the solver bounded in
+// time on huge components. Running out of budget never leads to a wrong
+// deduction, the component is simply left undecided.
+const (
+	COUNT_NODE_BUDGET = 200_000
+	PROBE_NODE_BUDGET = 20_000
+)
+
+// Budgets in use, which the tests lower to make them run out on real
+// boards
+var (
+	countNodeBudget = COUNT_NODE_BUDGET
+	probeNodeBudget = PROBE_NODE_BUDGET
+)
+
+type searchConstraint struct {
+	vars []int
+	rem  int
+}
+
+// componentSearch is a backtracking search over the bomb assignments of a
+// component, propagating the constraints after every decision
+type componentSearch struct {
+	constraints    []searchConstraint
+	varConstraints [][]int
+
+	assign     []int8 // -1 unassigned, 0 safe, 1 bomb
+	mines      []int  // per constraint, bombs assigned so far
+	unassigned []int  // per constraint, cells left unassigned
+	trail      []int
+
+	nodes  int
+	budget int
+}
+
+func newComponentSearch(component [][2]int, constraints map[[2]int]Constraint) *componentSearch {
+	indexOf := make(map[[2]int]int, len(component))
+	for i, u := range component {
+		indexOf[u] = i
+	}
+
+	cs := &componentSearch{
+		varConstraints: make([][]int, len(component)),
+		assign:         make([]int8, len(component)),
+		trail:          make([]int, 0, len(component)),
+	}
+	for i := range cs.assign {
+		cs.assign[i] = -1
+	}
+
+	// Unknowns of a constraint are always connected, so a constraint
+	// touching the component lies entirely inside it. They're added in
+	// reading order, which keeps the search within the same budget on
+	// every run.
+	for _, key := range slices.SortedFunc(maps.Keys(constraints), compareReadingOrder) {
+		constraint := constraints[key]
+		if len(constraint.UnknownNeighbors) == 0 {
+			continue
+		}
+		if _, ok := indexOf[constraint.UnknownNeighbors[0]]; !ok {
+			continue
+		}
+
+		sc := searchConstraint{
+			vars: make([]int, len(constraint.UnknownNeighbors)),
+			rem:  constraint.RemainingValue,
+		}
+		for i, u := range constraint.UnknownNeighbors {
+			sc.vars[i] = indexOf[u]
+			cs.varConstraints[sc.vars[i]] = append(cs.varConstraints[sc.vars[i]], len(cs.constraints))
+		}
+		cs.constraints = append(cs.constraints, sc)
+		cs.mines = append(cs.mines, 0)
+		cs.unassigned = append(cs.unassigned, len(sc.vars))
+	}
+
+	return cs
+}
+
+// set assigns a cell and reports whether every constraint it's part of
+// can still be satisfied
+func (cs *componentSearch) set(v int, val int8) bool {
+	cs.assign[v] = val
+	cs.trail = append(cs.trail, v)
+
+	ok := true
+	for _, c := range cs.varConstraints[v] {
+		cs.unassigned[c]--
+		if val == 1 {
+			cs.mines[c]++
+		}
+		if cs.mines[c] > cs.constraints[c].rem || cs.mines[c]+cs.unassigned[c] < cs.constraints[c].rem {
+			ok = false
+		}
+	}
+	return ok
+}
+
+// undo takes back every assignment made after the trail mark
+func (cs *componentSearch) undo(mark int) {
+	for len(cs.trail) > mark {
+		v := cs.trail[len(cs.trail)-1]
+		cs.trail = cs.trail[:len(cs.trail)-1]
+		for _, c := range cs.varConstraints[v] {
+			cs.unassigned[c]++
+			if cs.assign[v] == 1 {
+				cs.mines[c]--
+			}
+		}
+		cs.assign[v] = -1
+	}
+}
+
+// propagate assigns the cells forced by the queued constraints, until
+// nothing is forced anymore. It returns false on a conflict.
+func (cs *componentSearch) propagate(queue []int) bool {
+	queue = append([]int(nil), queue...)
+	for len(queue) > 0 {
+		c := queue[len(queue)-1]
+		queue = queue[:len(queue)-1]
+		if cs.unassigned[c] == 0 {
+			continue
+		}
+
+		var val int8
+		switch cs.constraints[c].rem {
+		case cs.mines[c]:
+			val = 0
+		case cs.mines[c] + cs.unassigned[c]:
+			val = 1
+		default:
+			continue
+		}
+		for _, v := range cs.constraints[c].vars {
+			if cs.assign[v] != -1 {
+				continue
+			}
+			if !cs.set(v, val) {
+				return false
+			}
+			queue = append(queue, cs.varConstraints[v]...)
+		}
+	}
+	return true
+}
+
+// restart clears every assignment and propagates all constraints
+func (cs *componentSearch) restart(budget int) bool {
+	cs.undo(0)
+	cs.nodes = 0
+	cs.budget = budget
+
+	all := make([]int, len(cs.constraints))
+	for c := range all {
+		all[c] = c
+	}
+	return cs.propagate(all)
+}
+
+func (cs *componentSearch) nextVar() int {
+	for v, val := range cs.assign {
+		if val == -1 {
+			return v
+		}
+	}
+	return -1
+}
+
+// dfs visits every complete assignment below the current state and calls
+// visit on each of them, stopping as soon as visit returns false.
+// completed is false when the node budget ran out.
+func (cs *componentSearch) dfs(visit func() bool) (completed bool, stopped bool) {
+	cs.nodes++
+	if cs.nodes > cs.budget {
+		return false, true
+	}
+
+	v := cs.nextVar()
+	if v < 0 {
+		return true, !visit()
+	}
+
+	for _, val := range []int8{0, 1} {
+		mark := len(cs.trail)
+		if cs.set(v, val) && cs.propagate(cs.varConstraints[v]) {
+			completed, stopped := cs.dfs(visit)
+			if stopped {
+				cs.undo(mark)
+				return completed, true
+			}
+		}
+		cs.undo(mark)
+	}
+	return true, false
+}
+
+// count tallies every valid assignment of the component. It returns nil
+// when the node budget runs out.
+func (cs *componentSearch) count(budget int) *componentSolutions {
+	N := len(cs.assign)
+	solutions := &componentSolutions{
+		total: make([]int, N+1),
+		bombs: make([][]int, N+1),
+	}
+	for k := range solutions.bombs {
+		solutions.bombs[k] = make([]int, N)
+	}
+
+	if !cs.restart(budget) {
+		return solutions
+	}
+	completed, _ := cs.dfs(func() bool {
+		k := 0
+		for _, val := range cs.assign {
+			k += int(val)
+		}
+		solutions.total[k]++
+		for i, val := range cs.assign {
+			if val == 1 {
+				solutions.bombs[k][i]++
+			}
+		}
+		return true
+	})
+	if !completed {
+		return nil
+	}
+
+	return solutions
+}
+
+// find looks for one valid assignment with cell v set to val (any
+// assignment when v is negative). It returns the assignment, or nil when
+// there's none; ok is false when the node budget ran out before knowing.
+func (cs *componentSearch) find(v int, val int8, budget int) (solution []int8, ok bool) {
+	if !cs.restart(budget) {
+		return nil, true
+	}
+	if v >= 0 {
+		if cs.assign[v] != -1 {
+			if cs.assign[v] != val {
+				return nil, true
+			}
+		} else if !cs.set(v, val) || !cs.propagate(cs.varConstraints[v]) {
+			return nil, true
+		}
+	}
+
+	completed, stopped := cs.dfs(func() bool {
+		solution = append([]int8(nil), cs.assign...)
+		return false
+	})
+	if solution != nil {
+		return solution, true
+	}
+	return nil, completed && !stopped
+}
+
+// probe decides, cell by cell, whether a bomb and whether no bomb are
+// possible, by looking for an assignment witnessing each. Every
+// assignment found witnesses all of its cells at once.
+func (cs *componentSearch) probe() (canBeMine, canBeSafe []bool, consistent bool) {
+	N := len(cs.assign)
+	canBeMine = make([]bool, N)
+	canBeSafe = make([]bool, N)
+	witness := func(solution []int8) {
+		for i, val := range solution {
+			if val == 1 {
+				canBeMine[i] = true
+			} else {
+				canBeSafe[i] = true
+			}
+		}
+	}
+
+	solution, ok := cs.find(-1, 0, probeNodeBudget)
+	if !ok {
+		// Not even one assignment in budget, nothing can be told
+		for i := range N {
+			canBeMine[i], canBeSafe[i] = true, true
+		}
+		return canBeMine, canBeSafe, true
+	}
+	if solution == nil {
+		return canBeMine, canBeSafe, false
+	}
+	witness(solution)
+
+	for i := range N {
+		for _, val := range []int8{0, 1} {
+			if (val == 1 && canBeMine[i]) || (val == 0 && canBeSafe[i]) {
+				continue
+			}
+			solution, ok := cs.find(i, val, probeNodeBudget)
+			if solution != nil {
+				witness(solution)
+			} else if !ok {
+				// Undecided within budget, assume possible
+				if val == 1 {
+					canBeMine[i] = true
+				} else {
+					canBeSafe[i] = true
+				}
+			}
+		}
+	}
+
+	return canBeMine, canBeSafe, true
+}
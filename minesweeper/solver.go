@@ -0,0 +1,695 @@
+package minesweeper
+
+import (
+	"maps"
+	"slices"
+)
+
+type Constraint struct {
+	UnknownNeighbors [][2]int
+	RemainingValue   int
+}
+
+type SolveResult struct {
+	// Consistent is false when no bomb layout matches the position
+	Consistent bool
+	// Safe lists the unknown cells that can't hold a bomb
+	Safe [][2]int
+	// Mines lists the unflagged cells that must hold a bomb
+	Mines [][2]int
+	// Undecided lists the unknown cells left neither safe nor bomb, none
+	// meaning the solver decides the whole position
+	Undecided [][2]int
+	// Probabilities holds the bomb probability of the undecided cells,
+	// rated like Probabilities does. The interior ones, touching no
+	// number, are only rated when the position's BombCount is known.
+	Probabilities map[[2]int]float64
+	// Trace lists the deductions in the order they were made
+	Trace []Step
+}
+
+// SolvePosition deduces everything it can from what a player can see:
+// revealed numbers and flags, flags being trusted as bombs. Unlike
+// DeterministicSolve it never looks at the actual bomb layout, so it works
+// on any live position. Components larger than maxComponentSize aren't
+// counted, only probed.
+func SolvePosition(p Position, maxComponentSize int) SolveResult {
+	s := newPositionSolver(p)
+	s.tracing = true
+	consistent := s.solve(maxComponentSize)
+
+	result := SolveResult{
+		Consistent: consistent,
+		Safe:       make([][2]int, 0, len(s.safe)),
+		Mines:      make([][2]int, 0, len(s.mines)),
+		Trace:      s.trace,
+	}
+	for pos := range s.safe {
+		result.Safe = append(result.Safe, pos)
+	}
+	for pos := range s.mines {
+		if p.Cells[pos[0]][pos[1]] == UNKNOWN {
+			result.Mines = append(result.Mines, pos)
+		}
+	}
+	slices.SortFunc(result.Safe, compareReadingOrder)
+	slices.SortFunc(result.Mines, compareReadingOrder)
+	if !consistent {
+		return result
+	}
+
+	for row := range p.Rows {
+		for col := range p.Cols {
+			if pos := [2]int{row, col}; s.isUnknown(pos) {
+				result.Undecided = append(result.Undecided, pos)
+			}
+		}
+	}
+	result.Probabilities, result.Consistent = s.probabilities(maxComponentSize)
+
+	return result
+}
+
+// positionSolver holds the deductions made on a position. Deduced safe
+// cells stay unrevealed, since their numbers aren't known.
+type positionSolver struct {
+	p     Position
+	mines map[[2]int]struct{} // flagged, revealed or deduced bombs
+	safe  map[[2]int]struct{} // deduced safe cells
+	rule  Rule                // rule behind the last round that made progress
+
+	// The trace of the deductions is only kept when tracing, the cells
+	// decided since the last recorded step waiting in newSafe and newMines
+	tracing  bool
+	trace    []Step
+	newSafe  [][2]int
+	newMines [][2]int
+}
+
+// Rule is the kind of deduction that made a solver round progress, from
+// the cheapest to the most expensive
+type Rule int
+
+const (
+	// RuleTrivial is a number whose bombs are all found (rem == 0) or
+	// whose unknowns are all bombs (rem == len(unk))
+	RuleTrivial Rule = iota
+	// RuleSubset compares two numbers sharing unknowns
+	RuleSubset
+	// RuleElimination is Gaussian elimination over a component
+	RuleElimination
+	// RuleSearch is the backtracking search over a component
+	RuleSearch
+	// RuleGlobal weighs the bombs left against every component
+	RuleGlobal
+)
+
+func newPositionSolver(p Position) *positionSolver {
+	s := &positionSolver{
+		p:     p,
+		mines: make(map[[2]int]struct{}),
+		safe:  make(map[[2]int]struct{}),
+	}
+	for row := range p.Rows {
+		for col := range p.Cols {
+			if v := p.Cells[row][col]; v == FLAGGED || v == BOMB {
+				s.mines[[2]int{row, col}] = struct{}{}
+			}
+		}
+	}
+	return s
+}
+
+// markSafe and markMine record a deduction, reporting whether it's new
+func (s *positionSolver) markSafe(pos [2]int) bool {
+	if _, ok := s.safe[pos]; ok {
+		return false
+	}
+	s.safe[pos] = struct{}{}
+	s.newSafe = append(s.newSafe, pos)
+	return true
+}
+
+func (s *positionSolver) markMine(pos [2]int) bool {
+	if _, ok := s.mines[pos]; ok {
+		return false
+	}
+	s.mines[pos] = struct{}{}
+	s.newMines = append(s.newMines, pos)
+	return true
+}
+
+func (s *positionSolver) isUnknown(pos [2]int) bool {
+	if s.p.Cells[pos[0]][pos[1]] != UNKNOWN {
+		return false
+	}
+	_, isMine := s.mines[pos]
+	_, isSafe := s.safe[pos]
+	return !isMine && !isSafe
+}
+
+// constraints builds one constraint per revealed number that still has
+// unknown neighbors. It returns false when a number can't be satisfied.
+func (s *positionSolver) constraints() (map[[2]int]Constraint, bool) {
+	constraints := make(map[[2]int]Constraint)
+	for row := range s.p.Rows {
+		for col := range s.p.Cols {
+			value := s.p.Cells[row][col]
+			if value < 0 {
+				continue
+			}
+
+			unk := make([][2]int, 0, 8)
+			bombs := 0
+			for _, neighbor := range s.p.getNeighborsOf(row, col) {
+				if _, ok := s.mines[neighbor]; ok {
+					bombs++
+				} else if s.isUnknown(neighbor) {
+					unk = append(unk, neighbor)
+				}
+			}
+
+			rem := value - bombs
+			if rem < 0 || rem > len(unk) {
+				return nil, false
+			}
+			if len(unk) > 0 {
+				constraints[[2]int{row, col}] = Constraint{
+					UnknownNeighbors: unk,
+					RemainingValue:   rem,
+				}
+			}
+		}
+	}
+	return constraints, true
+}
+
+// step runs one round of deductions, each stage running only when the
+// cheaper ones before it are stuck: the simple local rules, pairs of
+// constraints, Gaussian elimination, then a backtracking search per
+// component. Components up to maxComponentSize get their assignments
+// counted, larger ones are probed cell by cell.
+func (s *positionSolver) step(maxComponentSize int) (progress bool, consistent bool) {
+	constraints, ok := s.constraints()
+	if !ok {
+		return false, false
+	}
+	// Constraints are gone through in reading order, and so are the
+	// components (see frontierComponents), so the trace is the same from
+	// one run to the next
+	keys := slices.SortedFunc(maps.Keys(constraints), compareReadingOrder)
+
+	markSafe := func(pos [2]int) {
+		if s.markSafe(pos) {
+			progress = true
+		}
+	}
+	markMine := func(pos [2]int) {
+		if s.markMine(pos) {
+			progress = true
+		}
+	}
+
+	// --- Apply simple local rules (only on constraints/frontier) ---
+	for _, key := range keys {
+		constraint := constraints[key]
+		rem := constraint.RemainingValue
+		unk := constraint.UnknownNeighbors
+
+		if rem == 0 {
+			for _, u := range unk {
+				markSafe(u)
+			}
+			s.record(StepTrivialZero, constraints, [][2]int{key})
+		} else if rem == len(unk) {
+			for _, u := range unk {
+				markMine(u)
+			}
+			s.record(StepAllMines, constraints, [][2]int{key})
+		}
+	}
+	if progress {
+		s.rule = RuleTrivial
+		return true, true
+	}
+
+	// --- Compare the constraints sharing unknowns, two by two ---
+	deductions, consistent := reducePairs(keys, constraints)
+	if !consistent {
+		return false, false
+	}
+	for _, d := range deductions {
+		for _, u := range d.safe {
+			markSafe(u)
+		}
+		for _, u := range d.mines {
+			markMine(u)
+		}
+		s.record(StepSubset, constraints, d.keys)
+	}
+	if progress {
+		s.rule = RuleSubset
+		return true, true
+	}
+
+	// --- Linear algebra over each component catches the cheap forced moves ---
+	components := frontierComponents(constraints)
+	for _, component := range components {
+		safe, mines, consistent := eliminate(component, constraints)
+		if !consistent {
+			return false, false
+		}
+		for _, u := range safe {
+			markSafe(u)
+		}
+		for _, u := range mines {
+			markMine(u)
+		}
+		s.record(StepElimination, constraints, componentKeys(component, keys, constraints))
+	}
+	if progress {
+		s.rule = RuleElimination
+		return true, true
+	}
+
+	// --- Search each component for the assignments left ---
+	solutions := make([]*componentSolutions, len(components))
+	for ci, component := range components {
+		search := newComponentSearch(component, constraints)
+
+		// Small enough components get every assignment counted, which
+		// the bombs left are weighed against later on
+		if len(component) <= maxComponentSize {
+			if cs := search.count(countNodeBudget); cs != nil {
+				totalAssignments := cs.count()
+				// If inconsistency happens, position is invalid
+				if totalAssignments == 0 {
+					return false, false
+				}
+				solutions[ci] = cs
+
+				// Intersection across valid assignments using counts
+				for i, u := range component {
+					bombCount := cs.bombsOn(i)
+					if bombCount == totalAssignments {
+						markMine(u)
+					}
+					if bombCount == 0 {
+						markSafe(u)
+					}
+				}
+				s.record(StepEnumeration, constraints, componentKeys(component, keys, constraints))
+				continue
+			}
+		}
+
+		// Larger ones only get each cell probed for both states
+		canBeMine, canBeSafe, consistent := search.probe()
+		if !consistent {
+			return false, false
+		}
+		for i, u := range component {
+			if !canBeMine[i] {
+				markSafe(u)
+			} else if !canBeSafe[i] {
+				markMine(u)
+			}
+		}
+		s.record(StepEnumeration, constraints, componentKeys(component, keys, constraints))
+	}
+	if progress || s.p.BombCount <= 0 {
+		s.rule = RuleSearch
+		return progress, true
+	}
+
+	// --- Weigh the components against the bombs left ---
+	s.rule = RuleGlobal
+	progress, consistent = s.globalStep(components, solutions)
+	// Every number takes part in the count of the bombs left
+	s.record(StepGlobalCount, constraints, keys)
+	return progress, consistent
+}
+
+// globalStep combines the component solutions with the total bomb count:
+// the bombs used by all components, plus the ones left for the interior
+// cells (unknown cells touching no number), must add up to the bombs left.
+// An assignment only counts when such a combination exists. Components
+// that weren't counted (nil solutions) may use any number of bombs.
+func (s *positionSolver) globalStep(components [][][2]int, solutions []*componentSolutions) (progress bool, consistent bool) {
+	bombsLeft := s.p.BombCount - len(s.mines)
+	if bombsLeft < 0 {
+		return false, false
+	}
+
+	inFrontier := make(map[[2]int]struct{})
+	for _, component := range components {
+		for _, u := range component {
+			inFrontier[u] = struct{}{}
+		}
+	}
+	interior := make([][2]int, 0)
+	for row := range s.p.Rows {
+		for col := range s.p.Cols {
+			pos := [2]int{row, col}
+			if _, ok := inFrontier[pos]; !ok && s.isUnknown(pos) {
+				interior = append(interior, pos)
+			}
+		}
+	}
+
+	// The frontier must hold between lo and hi bombs, the interior
+	// taking the rest
+	lo, hi := bombsLeft-len(interior), bombsLeft
+
+	// Bomb counts each component can take
+	feasible := make([][]bool, len(components))
+	for ci, component := range components {
+		feasible[ci] = make([]bool, len(component)+1)
+		for k := range feasible[ci] {
+			feasible[ci][k] = solutions[ci] == nil || solutions[ci].total[k] > 0
+		}
+	}
+
+	// Reachable bomb sums (capped at the bombs left) of the components
+	// before (prefix) and after (suffix) each component
+	addCounts := func(sums []bool, counts []bool) []bool {
+		res := make([]bool, bombsLeft+1)
+		for sum, ok := range sums {
+			if !ok {
+				continue
+			}
+			for k, ok := range counts {
+				if ok && sum+k <= bombsLeft {
+					res[sum+k] = true
+				}
+			}
+		}
+		return res
+	}
+	n := len(components)
+	prefix := make([][]bool, n+1)
+	prefix[0] = make([]bool, bombsLeft+1)
+	prefix[0][0] = true
+	for ci := range n {
+		prefix[ci+1] = addCounts(prefix[ci], feasible[ci])
+	}
+	suffix := make([][]bool, n+1)
+	suffix[n] = make([]bool, bombsLeft+1)
+	suffix[n][0] = true
+	for ci := n - 1; ci >= 0; ci-- {
+		suffix[ci] = addCounts(suffix[ci+1], feasible[ci])
+	}
+
+	markSafe := func(pos [2]int) {
+		if s.markSafe(pos) {
+			progress = true
+		}
+	}
+	markMine := func(pos [2]int) {
+		if s.markMine(pos) {
+			progress = true
+		}
+	}
+
+	// --- Interior cells: all safe or all bombs when every combination agrees ---
+	interiorSafe, interiorMines, anyCombination := true, true, false
+	for sum := max(lo, 0); sum <= hi; sum++ {
+		if !prefix[n][sum] {
+			continue
+		}
+		anyCombination = true
+		interiorBombs := bombsLeft - sum
+		interiorSafe = interiorSafe && interiorBombs == 0
+		interiorMines = interiorMines && interiorBombs == len(interior)
+	}
+	if !anyCombination {
+		return false, false
+	}
+	for _, pos := range interior {
+		if interiorSafe {
+			markSafe(pos)
+		} else if interiorMines {
+			markMine(pos)
+		}
+	}
+
+	// --- Component cells: only count the assignments whose bomb count fits ---
+	for ci, cs := range solutions {
+		if cs == nil {
+			continue
+		}
+
+		// othersReach[sum] tells whether the other components can use
+		// exactly sum bombs
+		othersReach := make([]bool, bombsLeft+1)
+		for a, okA := range prefix[ci] {
+			if !okA {
+				continue
+			}
+			for b, okB := range suffix[ci+1] {
+				if okB && a+b <= bombsLeft {
+					othersReach[a+b] = true
+				}
+			}
+		}
+
+		fits := func(k int) bool {
+			for others := max(lo-k, 0); others <= hi-k; others++ {
+				if othersReach[others] {
+					return true
+				}
+			}
+			return false
+		}
+
+		minePossible := make([]bool, len(components[ci]))
+		safePossible := make([]bool, len(components[ci]))
+		for k, total := range cs.total {
+			if total == 0 || !fits(k) {
+				continue
+			}
+			for i := range components[ci] {
+				if cs.bombs[k][i] > 0 {
+					minePossible[i] = true
+				}
+				if cs.bombs[k][i] < total {
+					safePossible[i] = true
+				}
+			}
+		}
+		for i, u := range components[ci] {
+			if !minePossible[i] && !safePossible[i] {
+				return false, false
+			}
+			if !minePossible[i] {
+				markSafe(u)
+			} else if !safePossible[i] {
+				markMine(u)
+			}
+		}
+	}
+
+	return progress, true
+}
+
+// solve repeats deduction rounds until nothing changes. It returns false
+// when the position turns out to be inconsistent.
+func (s *positionSolver) solve(maxComponentSize int) bool {
+	for {
+		progress, consistent := s.step(maxComponentSize)
+		if !consistent {
+			return false
+		}
+		if !progress {
+			break
+		}
+	}
+
+	if s.p.BombCount > 0 && len(s.mines) > s.p.BombCount {
+		return false
+	}
+	return true
+}
+
+// DeterministicSolve plays the board from its start cell without guessing,
+// feeding every deduction of the position solver back as a click or a
+// flag. It reports whether every safe cell gets revealed, along with the
+// cells revealed and flagged on the way.
+func (m Minesweeper) DeterministicSolve(maxComponentSize int) (bool, map[[2]int]struct{}, map[[2]int]struct{}) {
+	return m.deterministicSolve(maxComponentSize, nil)
+}
+
+// SolveStats counts the deduction rounds of a DeterministicSolve run by
+// the rule that made them progress
+type SolveStats struct {
+	TrivialRounds     int
+	SubsetRounds      int
+	EliminationRounds int
+	SearchRounds      int
+	GlobalRounds      int
+}
+
+// Rounds is the total number of deduction rounds
+func (st SolveStats) Rounds() int {
+	return st.TrivialRounds + st.SubsetRounds + st.EliminationRounds + st.SearchRounds + st.GlobalRounds
+}
+
+func (m Minesweeper) deterministicSolve(maxComponentSize int, stats *SolveStats) (bool, map[[2]int]struct{}, map[[2]int]struct{}) {
+	revealed := make(map[[2]int]struct{})
+	flagged := make(map[[2]int]struct{})
+
+	// Start cell guard
+	if m.StartCell == nil {
+		return false, revealed, flagged
+	}
+
+	// Reveal a cell like a click would, flooding through clear cells
+	reveal := func(start [2]int) {
+		queue := [][2]int{start}
+		for len(queue) > 0 {
+			pos := queue[0]
+			queue = queue[1:]
+			if _, ok := revealed[pos]; ok {
+				continue
+			}
+
+			revealed[pos] = struct{}{}
+			// If clear/zero, add neighbors to queue (flood fill)
+			if m.Grid[pos[0]][pos[1]].Value == CLEAR {
+				for _, neighbor := range m.getNeighborsOf(pos[0], pos[1]) {
+					_, isRevealed := revealed[neighbor]
+					_, isFlagged := flagged[neighbor]
+					if !isRevealed && !isFlagged {
+						queue = append(queue, neighbor)
+					}
+				}
+			}
+		}
+	}
+
+	// Initial flood reveal from clicking start cell
+	reveal(m.StartCellPosition)
+
+	for {
+		s := newPositionSolver(m.positionFrom(revealed, flagged))
+		progress, consistent := s.step(maxComponentSize)
+		if !consistent {
+			return false, revealed, flagged
+		}
+		if !progress {
+			// Nothing forced -> guessing needed -> stuck
+			break
+		}
+		if stats != nil {
+			switch s.rule {
+			case RuleTrivial:
+				stats.TrivialRounds++
+			case RuleSubset:
+				stats.SubsetRounds++
+			case RuleElimination:
+				stats.EliminationRounds++
+			case RuleSearch:
+				stats.SearchRounds++
+			case RuleGlobal:
+				stats.GlobalRounds++
+			}
+		}
+
+		// Apply forced moves, newly revealed numbers feed the next round
+		for pos := range s.mines {
+			flagged[pos] = struct{}{}
+		}
+		for pos := range s.safe {
+			reveal(pos)
+		}
+	}
+
+	// Finished deduction loop, check if all safe cells are revealed
+	return len(revealed) == m.Rows*m.Cols-m.BombCount, revealed, flagged
+}
+
+// frontierComponents groups the frontier unknowns into connected
+// components, two unknowns being connected when they appear together in
+// a constraint. Cells are sorted in reading order and components by their
+// first cell, so the budgeted searches go through them the same way on
+// every run.
+func frontierComponents(constraints map[[2]int]Constraint) [][][2]int {
+	// --- Build adjacency among frontier unknowns (if they appear together in a constraint) ---
+	adj := make(map[[2]int]map[[2]int]struct{})
+	ensureAdj := func(a [2]int) {
+		if _, ok := adj[a]; !ok {
+			adj[a] = make(map[[2]int]struct{})
+		}
+	}
+	for _, constraint := range constraints {
+		unk := constraint.UnknownNeighbors
+		for i := range len(unk) {
+			// Ensure every unknown exists in adj (maybe isolated)
+			ensureAdj(unk[i])
+			for j := range len(unk) {
+				if i == j {
+					continue
+				}
+				adj[unk[i]][unk[j]] = struct{}{}
+			}
+		}
+	}
+
+	// --- Connected components on frontier graph ---
+	components := make([][][2]int, 0)
+	seen := make(map[[2]int]struct{})
+	for node := range adj {
+		if _, ok := seen[node]; ok {
+			continue
+		}
+		// BFS
+		component := make([][2]int, 0)
+		queue := [][2]int{node}
+		seen[node] = struct{}{}
+		for len(queue) > 0 {
+			curNode := queue[0]
+			queue = queue[1:]
+			component = append(component, curNode)
+			for neighbor := range adj[curNode] {
+				if _, ok := seen[neighbor]; !ok {
+					seen[neighbor] = struct{}{}
+					queue = append(queue, neighbor)
+				}
+			}
+		}
+		slices.SortFunc(component, compareReadingOrder)
+		components = append(components, component)
+	}
+
+	slices.SortFunc(components, func(a, b [][2]int) int {
+		return compareReadingOrder(a[0], b[0])
+	})
+	return components
+}
+
+// componentSolutions tallies the valid bomb assignments of a component
+// by the number of bombs they use
+type componentSolutions struct {
+	// total[k] counts the valid assignments using k bombs
+	total []int
+	// bombs[k][i] counts the ones of them putting a bomb on the i-th cell
+	bombs [][]int
+}
+
+// count is the number of valid assignments
+func (cs *componentSolutions) count() int {
+	n := 0
+	for _, t := range cs.total {
+		n += t
+	}
+	return n
+}
+
+// bombsOn is the number of valid assignments putting a bomb on the i-th cell
+func (cs *componentSolutions) bombsOn(i int) int {
+	n := 0
+	for k := range cs.bombs {
+		n += cs.bombs[k][i]
+	}
+	return n
+}
@@ -0,0 +1,138 @@
+package minesweeper
+
+import (
+	"maps"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// lowerBudgets makes the searches run out of budget on real boards, for
+// the rest of the test
+func lowerBudgets(t *testing.T) {
+	t.Cleanup(func() {
+		countNodeBudget, probeNodeBudget = COUNT_NODE_BUDGET, PROBE_NODE_BUDGET
+	})
+	countNodeBudget, probeNodeBudget = 10, 3
+}
+
+// solveOutcome is what DeterministicSolve tells of a board, cells in
+// reading order
+type solveOutcome struct {
+	solvable bool
+	revealed [][2]int
+	flagged  [][2]int
+}
+
+func solveBoard(m *Minesweeper, maxComponentSize int) solveOutcome {
+	solvable, revealed, flagged := m.DeterministicSolve(maxComponentSize)
+	return solveOutcome{
+		solvable: solvable,
+		revealed: slices.SortedFunc(maps.Keys(revealed), compareReadingOrder),
+		flagged:  slices.SortedFunc(maps.Keys(flagged), compareReadingOrder),
+	}
+}
+
+func (o solveOutcome) equal(other solveOutcome) bool {
+	return o.solvable == other.solvable && slices.Equal(o.revealed, other.revealed) && slices.Equal(o.flagged, other.flagged)
+}
+
+func TestDeterministicSolveWithinBudget(t *testing.T) {
+	boards := make([]*Minesweeper, 0)
+	full := make([]solveOutcome, 0)
+	for _, seed := range []int64{8, 11} {
+		m, err := GenerateBoardWithStartCell(DifficultyMap["expert"], seed)
+		if err != nil {
+			t.Fatal(err)
+		}
+		boards = append(boards, m)
+		full = append(full, solveBoard(m, 18))
+	}
+
+	lowerBudgets(t)
+	budgetReached := false
+	for i, m := range boards {
+		want := solveBoard(m, 18)
+		budgetReached = budgetReached || !want.equal(full[i])
+		// Map iteration order changes from one run to the next
+		for range 20 {
+			if got := solveBoard(m, 18); !got.equal(want) {
+				t.Fatalf("seed %d: %d cells revealed on one run, %d on another", m.Seed, len(want.revealed), len(got.revealed))
+			}
+		}
+	}
+	if !budgetReached {
+		t.Fatal("the lowered budgets never ran out, the boards don't test anything")
+	}
+}
+
+func TestSolvePositionPatterns(t *testing.T) {
+	tests := []struct {
+		name     string
+		position string
+		// Rule of the first step, the one the pattern is about
+		kind  StepKind
+		safe  [][2]int
+		mines [][2]int
+	}{
+		{
+			name:     "1-2-1",
+			position: "...\n121\n",
+			kind:     StepSubset,
+			safe:     [][2]int{{0, 1}},
+			mines:    [][2]int{{0, 0}, {0, 2}},
+		},
+		{
+			name:     "1-2-2-1",
+			position: "....\n1221\n",
+			kind:     StepSubset,
+			safe:     [][2]int{{0, 0}, {0, 3}},
+			mines:    [][2]int{{0, 1}, {0, 2}},
+		},
+		{
+			// The 1 at 1,0 leaves the 2 at 0,1 one bomb next to the 2 at
+			// 1,3, whose last cell takes the other
+			name:     "chained subsets",
+			position: ".2..\n1..2\n",
+			kind:     StepSubset,
+			mines:    [][2]int{{0, 3}},
+		},
+		{
+			// The 2 takes one bomb from each 1, no two numbers telling
+			// anything on their own
+			name:     "elimination only",
+			position: ".1..1.\n...2..\n",
+			kind:     StepElimination,
+			safe:     [][2]int{{0, 0}, {0, 5}, {1, 0}, {1, 1}, {1, 5}},
+		},
+		{
+			// No bomb on 2,2 would need one more around the 2 at 1,0
+			name:     "search only",
+			position: "...\n2.3\n.2.\n",
+			kind:     StepEnumeration,
+			mines:    [][2]int{{2, 2}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := ParsePosition(strings.NewReader(tt.position))
+			if err != nil {
+				t.Fatal(err)
+			}
+			result := SolvePosition(p, 18)
+			if !result.Consistent {
+				t.Fatal("the position is reported inconsistent")
+			}
+			if len(result.Trace) == 0 || result.Trace[0].Kind != tt.kind {
+				t.Errorf("trace %v, want a %v step first", result.Trace, tt.kind)
+			}
+			if !slices.Equal(result.Safe, tt.safe) {
+				t.Errorf("safe %v, want %v", result.Safe, tt.safe)
+			}
+			if !slices.Equal(result.Mines, tt.mines) {
+				t.Errorf("mines %v, want %v", result.Mines, tt.mines)
+			}
+		})
+	}
+}
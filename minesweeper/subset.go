@@ -0,0 +1,170 @@
+package minesweeper
+
+import "slices"
+
+// pairDeduction is what comparing two constraints forced. keys are the
+// numbers the two constraints come from, the subset's first when one's
+// unknowns are a subset of the other's.
+type pairDeduction struct {
+	keys  [][2]int
+	safe  [][2]int
+	mines [][2]int
+}
+
+// pairConstraint is a constraint of the working set of reducePairs,
+// either a number's or derived from two others, keys listing the numbers
+// it comes from
+type pairConstraint struct {
+	unknown [][2]int
+	rem     int
+	keys    [][2]int
+}
+
+// reducePairs compares every two constraints sharing unknowns, like the
+// 1-1 and 1-2-1 patterns. The shared cells hold at least and at most so
+// many bombs, which bounds the bombs left for the cells only one of them
+// touches: those are all safe or all bombs when a bound is tight. When one
+// constraint's unknowns are a subset of the other's, the other cells hold
+// the difference, which joins the constraints compared, for the subsets
+// to chain within the round. Constraints are gone through in the reading
+// order of keys, the derived ones after them. It returns false when a
+// pair can't be satisfied, or when a cell is forced both ways.
+func reducePairs(keys [][2]int, constraints map[[2]int]Constraint) ([]pairDeduction, bool) {
+	work := make([]pairConstraint, 0, len(keys))
+	// Constraints over each unknown, to find the pairs sharing one
+	byCell := make(map[[2]int][]int)
+	add := func(c pairConstraint) {
+		for _, u := range c.unknown {
+			byCell[u] = append(byCell[u], len(work))
+		}
+		work = append(work, c)
+	}
+	for _, key := range keys {
+		constraint := constraints[key]
+		add(pairConstraint{constraint.UnknownNeighbors, constraint.RemainingValue, [][2]int{key}})
+	}
+
+	deductions := make([]pairDeduction, 0)
+	forced := make(map[[2]int]bool) // whether a forced cell is a bomb
+	for i := 0; i < len(work); i++ {
+		b := work[i]
+
+		// --- Constraints sharing an unknown with b, earlier in order ---
+		partners := make([]int, 0)
+		for _, u := range b.unknown {
+			for _, j := range byCell[u] {
+				if j < i && !slices.Contains(partners, j) {
+					partners = append(partners, j)
+				}
+			}
+		}
+		slices.Sort(partners)
+
+		for _, j := range partners {
+			a := work[j]
+			onlyA, shared, onlyB := splitUnknowns(a.unknown, b.unknown)
+
+			// Bombs the shared cells can hold
+			lo := max(0, a.rem-len(onlyA), b.rem-len(onlyB))
+			hi := min(len(shared), a.rem, b.rem)
+			if lo > hi {
+				return nil, false
+			}
+
+			var d pairDeduction
+			consistent := true
+			force := func(only [][2]int, rem int) {
+				if len(only) == 0 {
+					return
+				}
+				var isMine bool
+				switch {
+				case rem-lo == 0:
+				case rem-hi == len(only):
+					isMine = true
+				default:
+					return
+				}
+				for _, u := range only {
+					if was, ok := forced[u]; ok && was != isMine {
+						consistent = false
+					}
+					forced[u] = isMine
+				}
+				if isMine {
+					d.mines = append(d.mines, only...)
+				} else {
+					d.safe = append(d.safe, only...)
+				}
+			}
+			force(onlyA, a.rem)
+			force(onlyB, b.rem)
+			if !consistent {
+				return nil, false
+			}
+
+			// --- The difference of a subset, unless forced already ---
+			if len(d.safe)+len(d.mines) == 0 && (len(onlyA) == 0) != (len(onlyB) == 0) {
+				sub, super, rest := a, b, onlyB
+				if len(onlyB) == 0 {
+					sub, super, rest = b, a, onlyA
+				}
+				if !slices.ContainsFunc(work, func(c pairConstraint) bool { return sameCells(c.unknown, rest) }) {
+					add(pairConstraint{rest, super.rem - sub.rem, mergeKeys(sub.keys, super.keys)})
+				}
+			}
+			if len(d.safe)+len(d.mines) == 0 {
+				continue
+			}
+
+			d.keys = mergeKeys(a.keys, b.keys)
+			if len(onlyB) == 0 {
+				d.keys = mergeKeys(b.keys, a.keys)
+			}
+			deductions = append(deductions, d)
+		}
+	}
+	return deductions, true
+}
+
+// mergeKeys appends the keys of b missing from a to those of a
+func mergeKeys(a, b [][2]int) [][2]int {
+	keys := slices.Clone(a)
+	for _, key := range b {
+		if !slices.Contains(keys, key) {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// sameCells reports whether two lists hold the same cells
+func sameCells(a, b [][2]int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for _, u := range a {
+		if !slices.Contains(b, u) {
+			return false
+		}
+	}
+	return true
+}
+
+// splitUnknowns splits the unknowns of two constraints into the ones only
+// the first touches, the shared ones and the ones only the second touches
+func splitUnknowns(a, b [][2]int) (onlyA, shared, onlyB [][2]int) {
+	for _, u := range a {
+		if slices.Contains(b, u) {
+			shared = append(shared, u)
+		} else {
+			onlyA = append(onlyA, u)
+		}
+	}
+	for _, u := range b {
+		if !slices.Contains(a, u) {
+			onlyB = append(onlyB, u)
+		}
+	}
+	return onlyA, shared, onlyB
+}
@@ -0,0 +1,227 @@
+package minesweeper
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// StepKind is the rule behind a solver step, from the cheapest to the
+// most expensive
+type StepKind int
+
+const (
+	// StepTrivialZero is a number whose bombs are all known, its other
+	// unknown neighbors being safe
+	StepTrivialZero StepKind = iota
+	// StepAllMines is a number with as many unknown neighbors as bombs
+	// left, which are all bombs
+	StepAllMines
+	// StepSubset compares two numbers sharing unknowns, the first one's
+	// unknowns being a subset of the second one's when they are. More
+	// numbers take part when subsets chain.
+	StepSubset
+	// StepElimination is Gaussian elimination over a component
+	StepElimination
+	// StepEnumeration is the search through every bomb layout of a
+	// component, all of them agreeing on the cells
+	StepEnumeration
+	// StepGlobalCount weighs the bombs left against every component
+	StepGlobalCount
+	stepKindCount
+)
+
+var stepKindNames = [stepKindCount]string{"trivial zero", "all mines", "subset", "elimination", "component enumeration", "global count"}
+
+func (k StepKind) String() string {
+	return stepKindNames[k]
+}
+
+func (k StepKind) MarshalText() ([]byte, error) {
+	return []byte(k.String()), nil
+}
+
+// StepConstraint is a revealed number as a step saw it
+type StepConstraint struct {
+	Position [2]int `json:"position"`
+	Value    int    `json:"value"`
+	// Remaining bombs around it, among its Unknown neighbors
+	Remaining int      `json:"remaining"`
+	Unknown   [][2]int `json:"unknown"`
+}
+
+// Step is one deduction of the solver: the rule applied, the numbers it
+// relies on and the cells it decided. BombsLeft is the number of bombs
+// not found yet, for global count steps.
+type Step struct {
+	Kind        StepKind         `json:"kind"`
+	Constraints []StepConstraint `json:"constraints"`
+	BombsLeft   int              `json:"bombsLeft,omitempty"`
+	Safe        [][2]int         `json:"safe"`
+	Mines       [][2]int         `json:"mines"`
+}
+
+// record turns the cells decided since the last call into a step of the
+// trace, keys being the numbers the deduction relied on
+func (s *positionSolver) record(kind StepKind, constraints map[[2]int]Constraint, keys [][2]int) {
+	defer func() {
+		s.newSafe, s.newMines = nil, nil
+	}()
+	if !s.tracing || len(s.newSafe)+len(s.newMines) == 0 {
+		return
+	}
+
+	st := Step{
+		Kind:        kind,
+		Constraints: make([]StepConstraint, 0, len(keys)),
+		Safe:        slices.SortedFunc(slices.Values(s.newSafe), compareReadingOrder),
+		Mines:       slices.SortedFunc(slices.Values(s.newMines), compareReadingOrder),
+	}
+	for _, key := range keys {
+		constraint := constraints[key]
+		st.Constraints = append(st.Constraints, StepConstraint{
+			Position:  key,
+			Value:     s.p.Cells[key[0]][key[1]],
+			Remaining: constraint.RemainingValue,
+			Unknown:   slices.SortedFunc(slices.Values(constraint.UnknownNeighbors), compareReadingOrder),
+		})
+	}
+	if kind == StepGlobalCount {
+		// The new mines are already counted as found
+		st.BombsLeft = s.p.BombCount - len(s.mines) + len(s.newMines)
+	}
+	s.trace = append(s.trace, st)
+}
+
+// componentKeys picks the keys of the constraints over a component, in
+// the order of keys
+func componentKeys(component [][2]int, keys [][2]int, constraints map[[2]int]Constraint) [][2]int {
+	inComponent := make([][2]int, 0)
+	for _, key := range keys {
+		// A constraint's unknowns all belong to the same component
+		if slices.Contains(component, constraints[key].UnknownNeighbors[0]) {
+			inComponent = append(inComponent, key)
+		}
+	}
+	return inComponent
+}
+
+// Decides reports whether the step decided the cell
+func (st Step) Decides(pos [2]int) bool {
+	return slices.Contains(st.Safe, pos) || slices.Contains(st.Mines, pos)
+}
+
+// Lines explains the step in plain words, one short line at a time.
+// Cells are written "row,col", counted from 0.
+func (st Step) Lines() []string {
+	lines := make([]string, 0)
+	switch st.Kind {
+	case StepTrivialZero:
+		lines = append(lines, "Every bomb around this number is known,", "so its other neighbors are safe.")
+	case StepAllMines:
+		lines = append(lines, "This number needs a bomb on each of its", "unknown neighbors.")
+	case StepSubset:
+		if len(st.Constraints) == 2 {
+			lines = append(lines, subsetLines(st.Constraints[0], st.Constraints[1])...)
+		} else {
+			lines = append(lines, "Taking these numbers' unknowns away from", "one another, subset by subset, forces", "the cells.")
+		}
+	case StepElimination:
+		lines = append(lines, "Adding and subtracting these numbers'", "equations forces the cells.")
+	case StepEnumeration:
+		lines = append(lines, "Every bomb layout matching these numbers", "agrees on the cells.")
+	case StepGlobalCount:
+		lines = append(lines, fmt.Sprintf("Only the layouts using the %s left", countBombs(st.BombsLeft)), "fit, and they all agree on the cells.")
+	}
+
+	lines = append(lines, "")
+	const MAX_CONSTRAINT_LINES = 8
+	for i, c := range st.Constraints {
+		if i == MAX_CONSTRAINT_LINES {
+			lines = append(lines, fmt.Sprintf("... and %d more numbers", len(st.Constraints)-i))
+			break
+		}
+		lines = append(lines, fmt.Sprintf("%d at %s: %d known, %d left among %d", c.Value, formatCell(c.Position), c.Value-c.Remaining, c.Remaining, len(c.Unknown)))
+	}
+	lines = append(lines, "")
+	lines = append(lines, cellLines("Safe", st.Safe)...)
+	lines = append(lines, cellLines("Bombs", st.Mines)...)
+	return lines
+}
+
+// subsetLines tells how two numbers sharing unknowns decide the cells
+// only one of them touches
+func subsetLines(a, b StepConstraint) []string {
+	onlyA, shared, onlyB := splitUnknowns(a.Unknown, b.Unknown)
+	if len(onlyA) == 0 {
+		others := "its other cell"
+		if len(onlyB) > 1 {
+			others = fmt.Sprintf("its %d other cells", len(onlyB))
+		}
+		return []string{
+			fmt.Sprintf("The %d at %s has all its unknowns", a.Value, formatCell(a.Position)),
+			fmt.Sprintf("next to the %d at %s, which leaves %s", b.Value, formatCell(b.Position), countBombs(b.Remaining-a.Remaining)),
+			fmt.Sprintf("for %s.", others),
+		}
+	}
+	lo := max(0, a.Remaining-len(onlyA), b.Remaining-len(onlyB))
+	hi := min(len(shared), a.Remaining, b.Remaining)
+	bombs := fmt.Sprintf("%d to %d bombs", lo, hi)
+	if lo == hi {
+		bombs = "exactly " + countBombs(lo)
+	}
+	return []string{
+		fmt.Sprintf("The %d at %s and the %d at %s share", a.Value, formatCell(a.Position), b.Value, formatCell(b.Position)),
+		fmt.Sprintf("%d cells, holding %s, which", len(shared), bombs),
+		"forces the cells only one of them touches.",
+	}
+}
+
+func countBombs(n int) string {
+	if n == 1 {
+		return "1 bomb"
+	}
+	return fmt.Sprintf("%d bombs", n)
+}
+
+func formatCell(pos [2]int) string {
+	return fmt.Sprintf("%d,%d", pos[0], pos[1])
+}
+
+// cellLines lists cells after a label, a few per line
+func cellLines(label string, cells [][2]int) []string {
+	const CELLS_PER_LINE = 6
+	if len(cells) == 0 {
+		return nil
+	}
+
+	lines := make([]string, 0)
+	for i := 0; i < len(cells); i += CELLS_PER_LINE {
+		strs := make([]string, 0, CELLS_PER_LINE)
+		for _, pos := range cells[i:min(i+CELLS_PER_LINE, len(cells))] {
+			strs = append(strs, formatCell(pos))
+		}
+		prefix := strings.Repeat(" ", len(label)+2)
+		if i == 0 {
+			prefix = label + ": "
+		}
+		lines = append(lines, prefix+strings.Join(strs, " "))
+	}
+	return lines
+}
+
+// Explain solves the player's view of the board and returns the step
+// deciding the cell, false when the solver can't decide it
+func (m *Minesweeper) Explain(pos [2]int, maxComponentSize int) (Step, bool) {
+	s := newPositionSolver(m.Position())
+	s.tracing = true
+	if !s.solve(maxComponentSize) {
+		return Step{}, false
+	}
+	for _, st := range s.trace {
+		if st.Decides(pos) {
+			return st, true
+		}
+	}
+	return Step{}, false
+}
@@ -0,0 +1,95 @@
+package minesweeper
+
+// ChangeSet is everything one move changed on the board, enough to take
+// it back and to play it again
+type ChangeSet struct {
+	// Revealed lists the cells the move revealed, flood fill included
+	Revealed [][2]int
+	// Toggled lists the cells whose flag the move toggled
+	Toggled [][2]int
+	// EndedGame is set when the move ended the game, Won telling how
+	EndedGame bool
+	Won       bool
+}
+
+func (m *Minesweeper) recordReveal(row, col int) {
+	if m.changes != nil {
+		m.changes.Revealed = append(m.changes.Revealed, [2]int{row, col})
+	}
+}
+
+func (m *Minesweeper) recordToggle(row, col int) {
+	if m.changes != nil {
+		m.changes.Toggled = append(m.changes.Toggled, [2]int{row, col})
+	}
+}
+
+// CanUndo reports whether there's a move to undo. A finished game can't
+// be undone, except for a loss in practice mode.
+func (m *Minesweeper) CanUndo() bool {
+	if len(m.history) == 0 {
+		return false
+	}
+	return !m.IsGameOver || (m.Practice && !m.IsWon)
+}
+
+func (m *Minesweeper) CanRedo() bool {
+	return len(m.future) > 0 && !m.IsGameOver
+}
+
+// Undo takes back the last move and marks the game as unranked
+func (m *Minesweeper) Undo() bool {
+	if !m.CanUndo() {
+		return false
+	}
+	cs := m.history[len(m.history)-1]
+	m.history = m.history[:len(m.history)-1]
+
+	for _, pos := range cs.Revealed {
+		cell := &m.Grid[pos[0]][pos[1]]
+		cell.Revealed = false
+		if cell.Value != BOMB {
+			m.RevealedCount--
+		}
+	}
+	for _, pos := range cs.Toggled {
+		m.Grid[pos[0]][pos[1]].Flagged = !m.Grid[pos[0]][pos[1]].Flagged
+	}
+	if cs.EndedGame {
+		m.IsGameOver = false
+		m.IsWon = false
+		m.StartClock()
+	}
+
+	m.future = append(m.future, cs)
+	m.Unranked = true
+	return true
+}
+
+// Redo plays the last undone move again
+func (m *Minesweeper) Redo() bool {
+	if !m.CanRedo() {
+		return false
+	}
+	cs := m.future[len(m.future)-1]
+	m.future = m.future[:len(m.future)-1]
+
+	for _, pos := range cs.Revealed {
+		cell := &m.Grid[pos[0]][pos[1]]
+		cell.Revealed = true
+		if cell.Value != BOMB {
+			m.RevealedCount++
+		}
+	}
+	for _, pos := range cs.Toggled {
+		m.Grid[pos[0]][pos[1]].Flagged = !m.Grid[pos[0]][pos[1]].Flagged
+	}
+	if cs.EndedGame {
+		m.IsGameOver = true
+		m.IsWon = cs.Won
+		m.StopClock()
+	}
+
+	m.history = append(m.history, cs)
+	return true
+}
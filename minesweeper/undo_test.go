@@ -0,0 +1,106 @@
+package minesweeper
+
+import (
+	"reflect"
+	"testing"
+)
+
+// boardState is what undo and redo must bring back
+type boardState struct {
+	position      Position
+	revealedCount int
+	isGameOver    bool
+	isWon         bool
+}
+
+func stateOf(m *Minesweeper) boardState {
+	return boardState{m.Position(), m.RevealedCount, m.IsGameOver, m.IsWon}
+}
+
+func TestUndoRedo(t *testing.T) {
+	// Bombs along the top row, the start cell flooding the rest of the
+	// board and leaving 0,1 and 0,3 to find
+	m, err := NewBoard(5, 5, [][2]int{{0, 0}, {0, 2}, {0, 4}}, [2]int{4, 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	moves := []struct {
+		kind     ActionKind
+		row, col int
+	}{
+		{ActionReveal, 4, 2},
+		{ActionFlag, 0, 0},
+		{ActionChord, 1, 0},
+		{ActionFlag, 0, 4},
+	}
+
+	states := []boardState{stateOf(m)}
+	for _, move := range moves {
+		m.Play(move.kind, move.row, move.col)
+		states = append(states, stateOf(m))
+	}
+	if m.IsGameOver || !m.Grid[0][1].Revealed {
+		t.Fatal("the moves didn't play out as the test expects")
+	}
+
+	for i := len(moves) - 1; i >= 0; i-- {
+		if !m.Undo() {
+			t.Fatalf("move %d: can't be undone", i)
+		}
+		if got := stateOf(m); !reflect.DeepEqual(got, states[i]) {
+			t.Errorf("undoing move %d: got %+v, want %+v", i, got, states[i])
+		}
+	}
+	if m.Undo() {
+		t.Error("undid more moves than were played")
+	}
+
+	for i := range moves {
+		if !m.Redo() {
+			t.Fatalf("move %d: can't be redone", i)
+		}
+		if got := stateOf(m); !reflect.DeepEqual(got, states[i+1]) {
+			t.Errorf("redoing move %d: got %+v, want %+v", i, got, states[i+1])
+		}
+	}
+	if m.Redo() {
+		t.Error("redid more moves than were undone")
+	}
+	if !m.Unranked {
+		t.Error("undoing a move left the game ranked")
+	}
+}
+
+func TestUndoLoss(t *testing.T) {
+	for _, practice := range []bool{false, true} {
+		m, err := NewBoard(5, 5, [][2]int{{0, 0}, {0, 2}, {0, 4}}, [2]int{4, 2})
+		if err != nil {
+			t.Fatal(err)
+		}
+		m.Practice = practice
+		m.Play(ActionReveal, 4, 2)
+		before := stateOf(m)
+
+		m.Play(ActionReveal, 0, 2)
+		if !m.IsGameOver || m.IsWon {
+			t.Fatal("stepping on a bomb didn't lose the game")
+		}
+		lost := stateOf(m)
+		if m.Undo() != practice {
+			t.Fatalf("practice %v: undoing the loss returned %v", practice, !practice)
+		}
+		if !practice {
+			continue
+		}
+
+		if got := stateOf(m); !reflect.DeepEqual(got, before) {
+			t.Errorf("undoing the loss: got %+v, want %+v", got, before)
+		}
+		if !m.Redo() {
+			t.Fatal("the loss can't be redone")
+		}
+		if got := stateOf(m); !reflect.DeepEqual(got, lost) {
+			t.Errorf("redoing the loss: got %+v, want %+v", got, lost)
+		}
+	}
+}
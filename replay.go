@@ -0,0 +1,136 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ahmadnaufalhakim/go-minesweeper/minesweeper"
+	"github.com/gdamore/tcell/v2"
+)
+
+var replaySpeeds = []float64{0.25, 0.5, 1, 2, 4, 8}
+
+func drawReplayHelpHint(screen tcell.Screen, opts *GameOptions) {
+	DrawHelpHint(screen, opts.Style, "Space = pause, n/Right = step, +/- = speed, r = restart, q = quit")
+}
+
+// RunReplay plays a recorded game back on its own clock, which can be
+// sped up, slowed down, paused and stepped one move at a time
+func RunReplay(screen tcell.Screen, r *minesweeper.Replay, opts *GameOptions) GameState {
+	screen.EnableMouse(tcell.MouseButtonEvents)
+	StopAllSounds()
+
+	m, err := r.NewBoard()
+	if err != nil {
+		ShowOverlay(
+			screen, FailedOverlayStyle,
+			[]string{
+				"Failed to load the replay!😭",
+				err.Error(),
+			},
+		)
+		return StateMenu
+	}
+
+	ticker := time.NewTicker(50 * time.Millisecond)
+	defer ticker.Stop()
+
+	speedIndex := 2
+	paused := false
+	// next is the index of the next move to play, clock the replay time
+	next := 0
+	var clock time.Duration
+	lastTick := time.Now()
+	cursor := NewCursor(m)
+
+	playNext := func() {
+		a := r.Actions[next]
+		if ok := m.Play(a.Kind, a.Row, a.Col); ok {
+			playRevealSound(m)
+		}
+		cursor.Row, cursor.Col = a.Row, a.Col
+		next++
+	}
+	restart := func() {
+		m, _ = r.NewBoard()
+		cursor = NewCursor(m)
+		next = 0
+		clock = 0
+	}
+
+	for {
+		// --- Advance the replay clock and play the moves it reached ---
+		now := time.Now()
+		if !paused && next < len(r.Actions) {
+			clock += time.Duration(float64(now.Sub(lastTick)) * replaySpeeds[speedIndex])
+		}
+		lastTick = now
+		for next < len(r.Actions) && r.Actions[next].At <= clock {
+			playNext()
+		}
+		if next == len(r.Actions) {
+			clock = min(clock, r.Duration())
+		}
+		// The HUD shows the replay clock, not the one Play started
+		m.StopClock()
+		m.Elapsed = clock
+
+		// --- Draw ---
+		screen.Clear()
+		DrawBackground(screen, opts.Background, m.IsGameOver && !m.IsWon)
+		DrawBoard(screen, m, opts.BorderStyle, opts.ShowInnerBorders)
+		DrawCursor(screen, m, cursor, opts.ShowInnerBorders)
+		DrawSmiley(screen, m, opts.Style, opts.ShowInnerBorders, tcell.ButtonNone, "")
+		DrawHUD(screen, m, opts.ShowInnerBorders)
+		DrawSeed(screen, m, opts.Style, opts.ShowInnerBorders)
+
+		status := fmt.Sprintf("Replay: move %d/%d, speed x%v", next, len(r.Actions), replaySpeeds[speedIndex])
+		if paused {
+			status += " (paused)"
+		}
+		_, h := screen.Size()
+		DrawCentered(screen, h-2, opts.Style, status)
+		drawReplayHelpHint(screen, opts)
+		screen.Show()
+
+		select {
+		case ev := <-screenEventCh:
+			switch ev := ev.(type) {
+			case *tcell.EventResize:
+				screen.Sync()
+			case *tcell.EventKey:
+				step := func() {
+					if next < len(r.Actions) {
+						clock = r.Actions[next].At
+						playNext()
+					}
+					paused = true
+				}
+
+				switch ev.Key() {
+				case tcell.KeyEsc:
+					return StateMenu
+				case tcell.KeyRight:
+					step()
+				case tcell.KeyRune:
+					switch ev.Rune() {
+					case ' ':
+						paused = !paused
+					case 'n':
+						step()
+					case '+', '=':
+						speedIndex = min(speedIndex+1, len(replaySpeeds)-1)
+					case '-':
+						speedIndex = max(speedIndex-1, 0)
+					case 'r':
+						StopAllSounds()
+						restart()
+					case 'q':
+						return StateMenu
+					}
+				}
+			}
+		case <-ticker.C:
+		}
+	}
+}
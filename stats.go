@@ -0,0 +1,217 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"iter"
+	"maps"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/ahmadnaufalhakim/go-minesweeper/minesweeper"
+)
+
+const (
+	// STATS_VERSION is bumped whenever the stats file layout changes
+	STATS_VERSION    = 1
+	BEST_TIMES_COUNT = 5
+)
+
+// The DifficultyMap presets, from the easiest
+var presetDifficulties = []string{"beginner", "intermediate", "advanced", "expert", "insane"}
+
+// Upper bounds of the win time histogram buckets, the last bucket
+// taking every time above them
+var histogramBounds = []time.Duration{
+	10 * time.Second,
+	30 * time.Second,
+	time.Minute,
+	2 * time.Minute,
+	5 * time.Minute,
+	10 * time.Minute,
+}
+
+// CategoryStats holds the games of one category. HintedWins counts the
+// wins using hints, out of Wins; files written before it was kept read
+// it as 0.
+type CategoryStats struct {
+	Wins          int     `json:"wins"`
+	Losses        int     `json:"losses"`
+	HintedWins    int     `json:"hintedWins"`
+	CurrentStreak int     `json:"currentStreak"`
+	BestStreak    int     `json:"bestStreak"`
+	BestTimesMs   []int64 `json:"bestTimesMs"`
+	Histogram     []int   `json:"histogram"`
+}
+
+// Stats holds the finished ranked games, per category (see StatsCategory)
+type Stats struct {
+	Version    int                       `json:"version"`
+	Categories map[string]*CategoryStats `json:"categories"`
+}
+
+func NewStats() *Stats {
+	return &Stats{
+		Version:    STATS_VERSION,
+		Categories: make(map[string]*CategoryStats),
+	}
+}
+
+// StatsCategory names the category of a board: the DifficultyMap preset
+// it matches or its custom size, with NG boards kept apart
+func StatsCategory(cfg minesweeper.DifficultyConfig, ng bool) string {
+	name := fmt.Sprintf("custom %dx%d/%d", cfg.Rows, cfg.Cols, cfg.BombCount)
+	for _, difficulty := range presetDifficulties {
+		if minesweeper.DifficultyMap[difficulty] == cfg {
+			name = difficulty
+			break
+		}
+	}
+	if ng {
+		name += " NG"
+	}
+	return name
+}
+
+// CategoryNames lists the preset categories first, then the custom
+// ones having any game recorded
+func (st *Stats) CategoryNames() []string {
+	return categoryNames(maps.Keys(st.Categories))
+}
+
+func categoryNames(recorded iter.Seq[string]) []string {
+	categories := make([]string, 0)
+	for _, ng := range []bool{false, true} {
+		for _, difficulty := range presetDifficulties {
+			categories = append(categories, StatsCategory(minesweeper.DifficultyMap[difficulty], ng))
+		}
+	}
+	custom := make([]string, 0)
+	for category := range recorded {
+		if !slices.Contains(categories, category) {
+			custom = append(custom, category)
+		}
+	}
+	slices.Sort(custom)
+	return append(categories, custom...)
+}
+
+// Category returns the stats of a category, empty when none was recorded
+func (st *Stats) Category(category string) *CategoryStats {
+	if c, ok := st.Categories[category]; ok {
+		return c
+	}
+	return &CategoryStats{Histogram: make([]int, len(histogramBounds)+1)}
+}
+
+// Record adds a finished game to its category. Wins using hints count as
+// wins, and as hinted wins, but leave the streak and the best times
+// alone.
+func (st *Stats) Record(m *minesweeper.Minesweeper) {
+	category := StatsCategory(minesweeper.DifficultyConfig{Rows: m.Rows, Cols: m.Cols, BombCount: m.BombCount}, m.NG)
+	c := st.Category(category)
+	st.Categories[category] = c
+
+	if !m.IsWon {
+		c.Losses++
+		c.CurrentStreak = 0
+		return
+	}
+
+	c.Wins++
+	elapsed := m.ElapsedTime()
+	bucket, _ := slices.BinarySearch(histogramBounds, elapsed)
+	c.Histogram[bucket]++
+	if m.HintsUsed > 0 {
+		c.HintedWins++
+		return
+	}
+
+	c.CurrentStreak++
+	c.BestStreak = max(c.BestStreak, c.CurrentStreak)
+
+	c.BestTimesMs = append(c.BestTimesMs, elapsed.Milliseconds())
+	slices.Sort(c.BestTimesMs)
+	if len(c.BestTimesMs) > BEST_TIMES_COUNT {
+		c.BestTimesMs = c.BestTimesMs[:BEST_TIMES_COUNT]
+	}
+}
+
+func (st *Stats) Reset(category string) {
+	delete(st.Categories, category)
+}
+
+func (c *CategoryStats) Played() int {
+	return c.Wins + c.Losses
+}
+
+// WinRate is the share of games won, in percent
+func (c *CategoryStats) WinRate() float64 {
+	if c.Played() == 0 {
+		return 0
+	}
+	return float64(c.Wins) * 100 / float64(c.Played())
+}
+
+var ErrCorruptStats = errors.New("statistics file is corrupt")
+
+// LoadStats reads the stats file, an absent file meaning no game was
+// recorded yet
+func LoadStats() (*Stats, error) {
+	path, err := dataPath(STATS_FILE_NAME)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return NewStats(), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	st := NewStats()
+	if err := json.Unmarshal(data, st); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrCorruptStats, err)
+	}
+	if st.Version != STATS_VERSION {
+		return nil, fmt.Errorf("%w: got version %d, expected %d", ErrCorruptStats, st.Version, STATS_VERSION)
+	}
+	if st.Categories == nil {
+		st.Categories = make(map[string]*CategoryStats)
+	}
+	for category, c := range st.Categories {
+		if c == nil || c.Wins < 0 || c.Losses < 0 || c.HintedWins < 0 || c.HintedWins > c.Wins || len(c.Histogram) != len(histogramBounds)+1 {
+			return nil, fmt.Errorf("%w: invalid category %q", ErrCorruptStats, category)
+		}
+	}
+
+	return st, nil
+}
+
+func SaveStats(st *Stats) error {
+	path, err := dataPath(STATS_FILE_NAME)
+	if err != nil {
+		return err
+	}
+
+	return writeFileAtomic(path, func(f *os.File) error {
+		enc := json.NewEncoder(f)
+		enc.SetIndent("", "  ")
+		return enc.Encode(st)
+	})
+}
+
+// RecordGame adds a finished game to the stats file. A corrupt file is
+// left untouched rather than overwritten.
+func RecordGame(m *minesweeper.Minesweeper) error {
+	st, err := LoadStats()
+	if err != nil {
+		return err
+	}
+	st.Record(m)
+	return SaveStats(st)
+}
@@ -0,0 +1,194 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/ahmadnaufalhakim/go-minesweeper/minesweeper"
+)
+
+const (
+	APP_DIR_NAME         = "go-minesweeper"
+	SAVE_FILE_NAME       = "save.json"
+	CORRUPT_FILE_SUFFIX  = ".corrupt"
+	REPLAY_FILE_NAME     = "replay.json"
+	STATS_FILE_NAME      = "stats.json"
+	HIGHSCORES_FILE_NAME = "highscores.json"
+	CONFIG_FILE_NAME     = "config.json"
+)
+
+// dataPath returns the path of a file inside the app directory in the
+// user's config directory, creating the directory when needed
+func dataPath(name string) (string, error) {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+
+	dir := filepath.Join(configDir, APP_DIR_NAME)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, name), nil
+}
+
+// writeFileAtomic writes to a temporary file first, so an interrupted
+// write never leaves a half-written file behind
+func writeFileAtomic(path string, write func(f *os.File) error) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := write(tmp); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
+
+func HasSavedGame() bool {
+	path, err := dataPath(SAVE_FILE_NAME)
+	if err != nil {
+		return false
+	}
+	_, err = os.Stat(path)
+	return err == nil
+}
+
+func SaveGame(m *minesweeper.Minesweeper) error {
+	path, err := dataPath(SAVE_FILE_NAME)
+	if err != nil {
+		return err
+	}
+
+	return writeFileAtomic(path, func(f *os.File) error {
+		return m.Save(f)
+	})
+}
+
+// LoadSavedGame restores the saved game and removes it from disk, so
+// the same game can't be continued twice. A save that can't be loaded is
+// renamed with CORRUPT_FILE_SUFFIX, for the menu to stop offering it.
+func LoadSavedGame() (*minesweeper.Minesweeper, error) {
+	path, err := dataPath(SAVE_FILE_NAME)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	m, err := minesweeper.Load(bytes.NewReader(data))
+	if err != nil {
+		corruptPath := path + CORRUPT_FILE_SUFFIX
+		if renameErr := os.Rename(path, corruptPath); renameErr != nil {
+			return nil, errors.Join(err, renameErr)
+		}
+		return nil, fmt.Errorf("%w (moved to %s)", err, filepath.Base(corruptPath))
+	}
+
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+func HasLastReplay() bool {
+	path, err := dataPath(REPLAY_FILE_NAME)
+	if err != nil {
+		return false
+	}
+	_, err = os.Stat(path)
+	return err == nil
+}
+
+// SaveLastReplay keeps the replay of a finished game, replacing the
+// previous one
+func SaveLastReplay(m *minesweeper.Minesweeper) error {
+	path, err := dataPath(REPLAY_FILE_NAME)
+	if err != nil {
+		return err
+	}
+
+	replay := m.Replay()
+	return writeFileAtomic(path, func(f *os.File) error {
+		return replay.Save(f)
+	})
+}
+
+func LoadLastReplay() (*minesweeper.Replay, error) {
+	path, err := dataPath(REPLAY_FILE_NAME)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return minesweeper.LoadReplay(f)
+}
+
+// ExportBoard writes the board in the text format to a new file named
+// after the current time, and returns its path
+func ExportBoard(m *minesweeper.Minesweeper) (string, error) {
+	path, err := dataPath("board-" + time.Now().Format("20060102-150405") + ".txt")
+	if err != nil {
+		return "", err
+	}
+
+	board := m.Board()
+	return path, writeFileAtomic(path, func(f *os.File) error {
+		return board.WriteText(f)
+	})
+}
+
+// LoadBoardFile reads a board in the JSON format when the file starts
+// with '{', in the text format otherwise
+func LoadBoardFile(path string) (*minesweeper.Minesweeper, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	rd := bufio.NewReader(f)
+	load := minesweeper.ParseBoard
+	for {
+		c, _, err := rd.ReadRune()
+		if err != nil {
+			break
+		}
+		if c == ' ' || c == '\t' || c == '\r' || c == '\n' {
+			continue
+		}
+		if c == '{' {
+			load = minesweeper.LoadBoard
+		}
+		rd.UnreadRune()
+		break
+	}
+
+	board, err := load(rd)
+	if err != nil {
+		return nil, err
+	}
+	return board.NewBoard()
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"cmp"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/ahmadnaufalhakim/go-minesweeper/minesweeper"
+)
+
+// sortedBoard puts the bombs of a board in reading order, the order the
+// text format gives them in
+func sortedBoard(m *minesweeper.Minesweeper) minesweeper.Board {
+	b := m.Board()
+	b.BombPositions = slices.Clone(b.BombPositions)
+	slices.SortFunc(b.BombPositions, func(p, q [2]int) int {
+		return cmp.Or(cmp.Compare(p[0], q[0]), cmp.Compare(p[1], q[1]))
+	})
+	return b
+}
+
+func TestLoadBoardFileRoundTrip(t *testing.T) {
+	m, err := minesweeper.GenerateBoardWithStartCell(minesweeper.DifficultyMap["intermediate"], 7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.Play(minesweeper.ActionReveal, m.StartCellPosition[0], m.StartCellPosition[1])
+	// A flag on a bomb and one on a safe cell, written F and f
+	flagged := make(map[bool]bool)
+	for row := range m.Rows {
+		for col := range m.Cols {
+			cell := m.Grid[row][col]
+			isBomb := cell.Value == minesweeper.BOMB
+			if !cell.Revealed && !flagged[isBomb] {
+				m.Play(minesweeper.ActionFlag, row, col)
+				flagged[isBomb] = true
+			}
+		}
+	}
+	if !flagged[true] || !flagged[false] {
+		t.Fatal("the board leaves no cell to flag")
+	}
+	want := sortedBoard(m)
+
+	board := m.Board()
+	for name, write := range map[string]func(f *os.File) error{
+		"board.txt":  func(f *os.File) error { return board.WriteText(f) },
+		"board.json": func(f *os.File) error { return board.Save(f) },
+	} {
+		path := filepath.Join(t.TempDir(), name)
+		if err := writeFileAtomic(path, write); err != nil {
+			t.Fatal(err)
+		}
+		loaded, err := LoadBoardFile(path)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if got := sortedBoard(loaded); !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: got %+v, want %+v", name, got, want)
+		}
+	}
+}
+
+func TestLoadBoardFileMalformed(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{"ragged rows", "*..\n...\n..\n"},
+		{"no bomb", "...\n...\n"},
+		{"only bombs", "**\n**\n"},
+		{"number not matching its bombs", "*2.\n...\n"},
+		{"unexpected character", "*..\n.?.\n"},
+		{"duplicate bomb", `{"version": 1, "rows": 2, "cols": 2, "bombPositions": [[0, 0], [0, 0]], "startCell": [-1, -1]}`},
+		{"bomb out of bounds", `{"version": 1, "rows": 2, "cols": 2, "bombPositions": [[2, 0]], "startCell": [-1, -1]}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "board")
+			if err := os.WriteFile(path, []byte(tt.content), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := LoadBoardFile(path); !errors.Is(err, minesweeper.ErrInvalidBoard) {
+				t.Errorf("got error %v, want %v", err, minesweeper.ErrInvalidBoard)
+			}
+		})
+	}
+}
@@ -1,6 +1,11 @@
 package main
 
-import "github.com/gdamore/tcell/v2"
+import (
+	"fmt"
+
+	"github.com/ahmadnaufalhakim/go-minesweeper/minesweeper"
+	"github.com/gdamore/tcell/v2"
+)
 
 type BorderStyle int
 
@@ -10,6 +15,24 @@ const (
 	borderStyleCount
 )
 
+var borderStyleNames = [borderStyleCount]string{"thin", "thick"}
+
+func (b BorderStyle) String() string {
+	if b < 0 || b >= borderStyleCount {
+		return fmt.Sprintf("BorderStyle(%d)", int(b))
+	}
+	return borderStyleNames[b]
+}
+
+func ParseBorderStyle(name string) (BorderStyle, error) {
+	for b, borderName := range borderStyleNames {
+		if borderName == name {
+			return BorderStyle(b), nil
+		}
+	}
+	return 0, fmt.Errorf("unknown border style %q", name)
+}
+
 var DefaultBorder = BorderThin
 var DefaultBorderStyle = tcell.StyleDefault.Background(COLOR_DARKGRAY).Foreground(tcell.ColorBlack)
 
@@ -27,16 +50,16 @@ var (
 	COLOR_EIGHT     = tcell.NewRGBColor(128, 128, 128)
 )
 var ValueToCellStyle = map[int]tcell.Style{
-	CLEAR: tcell.StyleDefault.Background(COLOR_LIGHTGRAY).Foreground(tcell.ColorReset).Bold(true),
-	BOMB:  tcell.StyleDefault.Background(tcell.ColorRed).Foreground(COLOR_BOMB).Bold(true),
-	1:     tcell.StyleDefault.Background(COLOR_LIGHTGRAY).Foreground(COLOR_ONE).Bold(true),
-	2:     tcell.StyleDefault.Background(COLOR_LIGHTGRAY).Foreground(COLOR_TWO).Bold(true),
-	3:     tcell.StyleDefault.Background(COLOR_LIGHTGRAY).Foreground(COLOR_THREE).Bold(true),
-	4:     tcell.StyleDefault.Background(COLOR_LIGHTGRAY).Foreground(COLOR_FOUR).Bold(true),
-	5:     tcell.StyleDefault.Background(COLOR_LIGHTGRAY).Foreground(COLOR_FIVE).Bold(true),
-	6:     tcell.StyleDefault.Background(COLOR_LIGHTGRAY).Foreground(COLOR_SIX).Bold(true),
-	7:     tcell.StyleDefault.Background(COLOR_LIGHTGRAY).Foreground(COLOR_SEVEN).Bold(true),
-	8:     tcell.StyleDefault.Background(COLOR_LIGHTGRAY).Foreground(COLOR_EIGHT).Bold(true),
+	minesweeper.CLEAR: tcell.StyleDefault.Background(COLOR_LIGHTGRAY).Foreground(tcell.ColorReset).Bold(true),
+	minesweeper.BOMB:  tcell.StyleDefault.Background(tcell.ColorRed).Foreground(COLOR_BOMB).Bold(true),
+	1:                 tcell.StyleDefault.Background(COLOR_LIGHTGRAY).Foreground(COLOR_ONE).Bold(true),
+	2:                 tcell.StyleDefault.Background(COLOR_LIGHTGRAY).Foreground(COLOR_TWO).Bold(true),
+	3:                 tcell.StyleDefault.Background(COLOR_LIGHTGRAY).Foreground(COLOR_THREE).Bold(true),
+	4:                 tcell.StyleDefault.Background(COLOR_LIGHTGRAY).Foreground(COLOR_FOUR).Bold(true),
+	5:                 tcell.StyleDefault.Background(COLOR_LIGHTGRAY).Foreground(COLOR_FIVE).Bold(true),
+	6:                 tcell.StyleDefault.Background(COLOR_LIGHTGRAY).Foreground(COLOR_SIX).Bold(true),
+	7:                 tcell.StyleDefault.Background(COLOR_LIGHTGRAY).Foreground(COLOR_SEVEN).Bold(true),
+	8:                 tcell.StyleDefault.Background(COLOR_LIGHTGRAY).Foreground(COLOR_EIGHT).Bold(true),
 }
 var DifficultyToStyle = map[string]tcell.Style{
 	"beginner":     tcell.StyleDefault.Background(tcell.ColorDarkBlue),
@@ -50,6 +73,11 @@ var DifficultyToStyle = map[string]tcell.Style{
 var DefaultStyle = tcell.StyleDefault.Background(COLOR_LIGHTGRAY).Foreground(tcell.ColorBlack)
 var SelectedStyle = tcell.StyleDefault.Background(tcell.ColorOrange).Foreground(tcell.ColorBlack)
 var FlagStyle = tcell.StyleDefault.Background(tcell.ColorOrange).Foreground(tcell.ColorDarkRed)
+var CursorStyle = tcell.StyleDefault.Background(tcell.ColorDodgerBlue).Foreground(tcell.ColorWhite).Bold(true)
+var HintSafeStyle = tcell.StyleDefault.Background(tcell.ColorLimeGreen).Foreground(tcell.ColorBlack).Bold(true)
+var HintRiskStyle = tcell.StyleDefault.Background(tcell.ColorYellow).Foreground(tcell.ColorBlack).Bold(true)
+var HintSupportStyle = tcell.StyleDefault.Background(tcell.ColorAqua).Foreground(tcell.ColorBlack).Bold(true)
+var HUDStyle = tcell.StyleDefault.Background(tcell.ColorBlack).Foreground(tcell.ColorRed).Bold(true)
 var StartCellStyle = tcell.StyleDefault.Background(tcell.ColorLimeGreen).Foreground(tcell.ColorWhiteSmoke)
 
 var DefaultOverlayStyle = tcell.StyleDefault.Background(tcell.ColorDarkOrange).Foreground(tcell.ColorBlack)
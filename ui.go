@@ -2,6 +2,8 @@ package main
 
 import (
 	"slices"
+	"strings"
+	"time"
 
 	"github.com/ahmadnaufalhakim/go-minesweeper/assets"
 	"github.com/gdamore/tcell/v2"
@@ -31,6 +33,27 @@ func DrawString(screen tcell.Screen, x, y int, style tcell.Style, s string) {
 	}
 }
 
+// DrawHelpHint draws a help message in the bottom right corner. A message
+// too wide for the screen is wrapped after its commas, the lines going up
+// from the bottom.
+func DrawHelpHint(screen tcell.Screen, style tcell.Style, message string) {
+	w, h := screen.Size()
+	lines := make([]string, 0)
+	for _, part := range strings.Split(message, ", ") {
+		if last := len(lines) - 1; last >= 0 && len(lines[last])+len(", "+part)+1 <= w {
+			lines[last] += ", " + part
+			continue
+		}
+		if last := len(lines) - 1; last >= 0 {
+			lines[last] += ","
+		}
+		lines = append(lines, part)
+	}
+	for i, line := range lines {
+		DrawString(screen, max(0, w-len(line)-1), h-len(lines)+i, style, line)
+	}
+}
+
 func DrawCentered(screen tcell.Screen, y int, style tcell.Style, str string) {
 	w, _ := screen.Size()
 	x := (w-len(str))/2 - len(str)%2
@@ -87,6 +110,14 @@ func DrawOverlay(screen tcell.Screen, style tcell.Style, strs []string, marginX,
 	}
 }
 
+// ShowOverlay shows a message overlay on a cleared screen for a moment
+func ShowOverlay(screen tcell.Screen, style tcell.Style, strs []string) {
+	screen.Clear()
+	DrawOverlay(screen, style, strs, DEFAULT_MARGIN_X, DEFAULT_MARGIN_Y)
+	screen.Show()
+	time.Sleep(2000 * time.Millisecond)
+}
+
 func (s *Sprite) Draw(screen tcell.Screen, style tcell.Style) {
 	screen.SetContent(s.X, s.Y, s.Char, nil, style)
 }